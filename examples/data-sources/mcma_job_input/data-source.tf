@@ -0,0 +1,26 @@
+data "mcma_job_input" "transcode" {
+  job_profile_name = "TranscodeFile"
+
+  job_input = jsonencode({
+    inputFile = {
+      "@type" = "S3Locator"
+      url     = "s3://reference-assets/sample.mp4"
+    }
+    format = "mp4"
+  })
+}
+
+# report the problems instead of failing
+data "mcma_job_input" "report_only" {
+  job_profile_name         = "TranscodeFile"
+  allow_unknown_parameters = true
+  fail_on_error            = false
+
+  job_input = jsonencode({
+    format = "avi"
+  })
+}
+
+output "job_input_errors" {
+  value = data.mcma_job_input.report_only.errors
+}
@@ -0,0 +1,16 @@
+data "mcma_job_profile" "extract_technical_metadata" {
+  name = "ExtractTechnicalMetadata"
+}
+
+resource "mcma_service" "mediainfo_ame_service" {
+  name      = "MediaInfo AME Service"
+  auth_type = "AWS4"
+  job_type  = "AmeJob"
+  resource {
+    resource_type = "JobAssignment"
+    http_endpoint = "https://service.mcma.io/api/job-assignments"
+  }
+  job_profile_ids = [
+    data.mcma_job_profile.extract_technical_metadata.id,
+  ]
+}
@@ -0,0 +1,11 @@
+data "mcma_job_profiles" "transcode" {
+  name_prefix          = "Transcode"
+  input_parameter_name = "inputFile"
+  custom_properties = {
+    tier = "premium"
+  }
+}
+
+output "transcode_job_profile_ids" {
+  value = data.mcma_job_profiles.transcode.ids
+}
@@ -0,0 +1,12 @@
+data "mcma_resource" "bm_content" {
+  type = "BMContent"
+  id   = "https://service.mcma.io/api/bm-contents/12345"
+}
+
+output "bm_content_name" {
+  value = jsondecode(data.mcma_resource.bm_content.resource_json).metadata.name
+}
+
+output "bm_content_status" {
+  value = data.mcma_resource.bm_content.attributes["status"]
+}
@@ -0,0 +1,10 @@
+data "mcma_resources" "bm_essences" {
+  type = "BMEssence"
+  query = {
+    status = "COMPLETED"
+  }
+}
+
+output "bm_essence_ids" {
+  value = data.mcma_resources.bm_essences.ids
+}
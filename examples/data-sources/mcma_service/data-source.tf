@@ -0,0 +1,11 @@
+data "mcma_service" "by_name" {
+  name = "MediaInfo AME Service"
+}
+
+data "mcma_service" "by_id" {
+  id = "https://service-registry-example.mcma.io/api/services/12345"
+}
+
+output "job_assignments_url" {
+  value = data.mcma_service.by_name.http_endpoints["JobAssignment"]
+}
@@ -0,0 +1,12 @@
+data "mcma_services" "ame" {
+  job_type      = "AmeJob"
+  resource_type = "JobAssignment"
+}
+
+data "mcma_services" "by_name" {
+  name_regex = "^MediaInfo"
+}
+
+output "ame_service_ids" {
+  value = data.mcma_services.ame.ids
+}
@@ -22,10 +22,99 @@ provider "mcma" {
   }
 }
 
+# AWS auth assuming a role
+provider "mcma" {
+  service_registry_url = "https://service-registry-example.mcma.io/api/"
+  aws4_auth {
+    region  = "us-east-1"
+    profile = "myprofile"
+    assume_role {
+      role_arn    = "arn:aws:iam::123456789012:role/mcma-deployer"
+      external_id = "my-external-id"
+      duration    = "1h"
+    }
+  }
+}
+
 # MCMA API Key auth
 provider "mcma" {
   service_registry_url = "https://service-registry-example.mcma.io/api/"
   mcma_api_key_auth {
     api_key = "abcd1234efgh5678"
   }
-}
\ No newline at end of file
+}
+
+# Multiple AWS accounts and API keys, selected by the auth_type of services and resource endpoints
+provider "mcma" {
+  service_registry_url       = "https://service-registry-example.mcma.io/api/"
+  service_registry_auth_type = "AWS4-Main"
+  aws4_auth {
+    auth_type = "AWS4-Main"
+    region    = "us-east-1"
+    profile   = "main"
+  }
+  aws4_auth {
+    auth_type = "AWS4-Media"
+    region    = "eu-west-1"
+    profile   = "media"
+  }
+  mcma_api_key_auth {
+    api_key = "abcd1234efgh5678"
+  }
+  mcma_api_key_auth {
+    auth_type = "McmaApiKey-Partner"
+    api_key   = "ijkl9012mnop3456"
+  }
+}
+
+# OAuth2 client credentials auth
+provider "mcma" {
+  service_registry_url = "https://service-registry-example.mcma.io/api/"
+  oauth2_client_credentials_auth {
+    token_url     = "https://auth.example.com/oauth2/token"
+    client_id     = "myclientid"
+    client_secret = "myclientsecret"
+    scopes        = ["mcma/read", "mcma/write"]
+  }
+}
+
+# Bearer token (JWT) auth, re-reading the token from a file for every request
+provider "mcma" {
+  service_registry_url = "https://service-registry-example.mcma.io/api/"
+  bearer_token_auth {
+    auth_type  = "JWT"
+    token_file = "/var/run/secrets/mcma/token"
+  }
+}
+
+# Private CA and mutual TLS
+provider "mcma" {
+  service_registry_url = "https://service-registry.mcma.internal/api/"
+  tls {
+    ca_cert_file = "/etc/pki/mcma/ca.pem"
+    client_cert  = file("/etc/pki/mcma/client.pem")
+    client_key   = file("/etc/pki/mcma/client-key.pem")
+  }
+}
+
+# Custom retry policy for transient errors
+provider "mcma" {
+  service_registry_url = "https://service-registry-example.mcma.io/api/"
+  retry {
+    max_attempts           = 6
+    min_backoff            = "500ms"
+    max_backoff            = "1m"
+    retryable_status_codes = [429, 500, 502, 503, 504]
+  }
+}
+
+# Fail updates of resources that were modified in the service registry since they were last read
+provider "mcma" {
+  service_registry_url = "https://service-registry-example.mcma.io/api/"
+  conflict_detection   = true
+}
+
+# All settings from environment variables, e.g.
+#   MCMA_SERVICE_REGISTRY_URL=https://service-registry-example.mcma.io/api/
+#   MCMA_API_KEY=abcd1234efgh5678
+provider "mcma" {}
@@ -0,0 +1,27 @@
+resource "mcma_job" "ingest_reference_asset" {
+  job_type         = "AmeJob"
+  job_profile_name = "ExtractTechnicalMetadata"
+
+  job_input = jsonencode({
+    inputFile = {
+      "@type" = "S3Locator"
+      url     = "s3://reference-assets/sample.mp4"
+    }
+  })
+
+  # run the job again when the reference asset changes
+  triggers = {
+    asset_etag = "d41d8cd98f00b204e9800998ecf8427e"
+  }
+
+  # cancel the job if it is still running when the resource is destroyed
+  cancel_on_destroy = true
+
+  timeouts {
+    create = "1h"
+  }
+}
+
+output "technical_metadata" {
+  value = jsondecode(mcma_job.ingest_reference_asset.job_output)
+}
@@ -0,0 +1,5 @@
+# Job profiles can be imported by ID
+terraform import mcma_job_profile.extract_technical_metadata https://service-registry.mcma.io/api/job-profiles/12345
+
+# or by name
+terraform import mcma_job_profile.extract_technical_metadata ExtractTechnicalMetadata
@@ -24,4 +24,51 @@ resource "mcma_job_profile" "example" {
     customprop1 = "customprop1val"
     customprop2 = "customprop2val"
   }
-}
\ No newline at end of file
+}
+
+resource "mcma_job_profile" "example_json" {
+  name = "example_json"
+
+  input_parameter {
+    name = "inputFile"
+    type = "Locator"
+  }
+
+  # custom properties that are not strings
+  custom_properties_json = jsonencode({
+    tier     = "premium"
+    priority = 2
+    limits = {
+      maxDuration = 3600
+    }
+  })
+}
+
+resource "mcma_job_profile" "example_ordered" {
+  name = "example_ordered"
+
+  # keep the parameters in the order declared here
+  ordered_input_parameter {
+    name        = "inputFile"
+    type        = "Locator"
+    description = "The file to transcode"
+  }
+  ordered_input_parameter {
+    name           = "format"
+    type           = "string"
+    description    = "The container format of the output file"
+    default_value  = "mp4"
+    allowed_values = ["mp4", "mov"]
+  }
+  ordered_input_parameter {
+    name     = "bitrate"
+    type     = "number"
+    optional = true
+  }
+
+  ordered_output_parameter {
+    name        = "outputFile"
+    type        = "Locator"
+    description = "The transcoded file"
+  }
+}
@@ -0,0 +1,5 @@
+# Resources can be imported by <type>,<id>
+terraform import mcma_resource.bm_content BMContent,https://service.mcma.io/api/bm-contents/12345
+
+# or by ID only, in which case the type is inferred from the service endpoint and the @type of the resource
+terraform import mcma_resource.bm_content https://service.mcma.io/api/bm-contents/12345
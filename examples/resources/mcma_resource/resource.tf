@@ -6,4 +6,24 @@ resource "mcma_resource" "bm_content" {
       description = "Test asset"
     }
   })
-}
\ No newline at end of file
+
+  # properties set by the service that should not produce a plan
+  ignore_fields = ["status", "metadata.checksum"]
+}
+
+resource "mcma_resource" "bm_essence" {
+  type = "BMEssence"
+  resource_json = jsonencode({
+    title = "Terraform provider test BMEssence"
+  })
+
+  # values set by the service, exposed in output_values
+  outputs = {
+    status = "$.status"
+    url    = "$.locators[0].url"
+  }
+}
+
+output "bm_essence_status" {
+  value = mcma_resource.bm_essence.output_values["status"]
+}
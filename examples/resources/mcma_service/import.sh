@@ -0,0 +1,5 @@
+# Services can be imported by ID
+terraform import mcma_service.mediainfo_ame_service https://service-registry.mcma.io/api/services/12345
+
+# or by name
+terraform import mcma_service.mediainfo_ame_service "MediaInfo AME Service"
@@ -17,4 +17,20 @@ resource "mcma_service" "example" {
     "https://service.registry.com/api/job-profiles/12345",
     "https://service.registry.com/api/job-profiles/67890"
   ]
-}
\ No newline at end of file
+}
+resource "mcma_service" "example_timeouts" {
+  name      = "example_timeouts"
+  auth_type = "AWS4"
+  job_type  = "AmeJob"
+
+  resource {
+    resource_type = "JobAssignment"
+    http_endpoint = "https://some.endpoint.com/api/job-assignments"
+  }
+
+  # wait longer for a slow service registry to return the service after it was created or updated
+  timeouts {
+    create = "10m"
+    update = "10m"
+  }
+}
@@ -0,0 +1,60 @@
+// Package mcmaclient is the client of the MCMA service registry and of the services it lists, as used by the
+// provider. It finds the endpoint of each resource type in the registry, and authenticates every request with the
+// authenticator registered for the auth type of that endpoint.
+package mcmaclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// Authenticator adds the credentials of an auth type to a request.
+type Authenticator interface {
+	Authenticate(request *http.Request) error
+}
+
+type mcmaApiKeyAuthenticator struct {
+	apiKey string
+}
+
+// NewMcmaApiKeyAuthenticator returns an authenticator that sends the given key in the x-mcma-api-key header.
+func NewMcmaApiKeyAuthenticator(apiKey string) Authenticator {
+	return &mcmaApiKeyAuthenticator{apiKey: apiKey}
+}
+
+func (a *mcmaApiKeyAuthenticator) Authenticate(request *http.Request) error {
+	request.Header.Set("x-mcma-api-key", a.apiKey)
+	return nil
+}
+
+type aws4Authenticator struct {
+	signer *v4.Signer
+	region string
+}
+
+// NewAWS4Authenticator returns an authenticator that signs requests to API Gateway with AWS signature version 4.
+// The credentials are retrieved again when they expire, e.g. those of an assumed role.
+func NewAWS4Authenticator(creds *credentials.Credentials, region string) Authenticator {
+	return &aws4Authenticator{signer: v4.NewSigner(creds), region: region}
+}
+
+func (a *aws4Authenticator) Authenticate(request *http.Request) error {
+	// the signer hashes the body and attaches it to the request again
+	var body io.ReadSeeker
+	if request.Body != nil {
+		bodyBytes, err := io.ReadAll(request.Body)
+		if err != nil {
+			return err
+		}
+		_ = request.Body.Close()
+		body = bytes.NewReader(bodyBytes)
+	}
+
+	_, err := a.signer.Sign(request, body, "execute-api", a.region, time.Now())
+	return err
+}
@@ -0,0 +1,332 @@
+package mcmaclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+)
+
+// ResourceManager reads and writes the resources of the services registered in an MCMA service registry. Requests are
+// sent with the context passed to each method, so that they end with the operation that sends them.
+type ResourceManager struct {
+	servicesUrl      string
+	servicesAuthType string
+	authenticators   map[string]Authenticator
+	httpClient       *http.Client
+}
+
+// NewResourceManager returns a resource manager for the service registry at the given url, e.g.
+// https://service-registry.example.com/api/, whose services endpoint requires the given auth type.
+func NewResourceManager(serviceRegistryUrl string, serviceRegistryAuthType string) ResourceManager {
+	return ResourceManager{
+		servicesUrl:      strings.TrimSuffix(serviceRegistryUrl, "/") + "/services",
+		servicesAuthType: serviceRegistryAuthType,
+		authenticators:   make(map[string]Authenticator),
+		httpClient:       http.DefaultClient,
+	}
+}
+
+// NewResourceManagerNoAuth returns a resource manager for a service registry whose services endpoint does not require
+// authentication.
+func NewResourceManagerNoAuth(serviceRegistryUrl string) ResourceManager {
+	return NewResourceManager(serviceRegistryUrl, "")
+}
+
+// AddAuth registers the authenticator used for the endpoints with the given auth type.
+func (rm *ResourceManager) AddAuth(authType string, authenticator Authenticator) {
+	rm.authenticators[authType] = authenticator
+}
+
+// SetHttpClient sets the client that sends the requests, which defaults to http.DefaultClient.
+func (rm *ResourceManager) SetHttpClient(httpClient *http.Client) {
+	rm.httpClient = httpClient
+}
+
+// Query returns the resources of the type of t, e.g. mcmamodel.Service, whose properties have the values in filter.
+// The results are values of type t.
+func (rm *ResourceManager) Query(ctx context.Context, t reflect.Type, filter map[string]string) ([]interface{}, error) {
+	endpointUrl, authType, err := rm.getResourceEndpoint(ctx, t.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var rawResults []json.RawMessage
+	if err = rm.query(ctx, endpointUrl, authType, filter, &rawResults); err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, 0, len(rawResults))
+	for _, rawResult := range rawResults {
+		result := reflect.New(t)
+		if err = json.Unmarshal(rawResult, result.Interface()); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %s", t.Name(), err)
+		}
+		results = append(results, result.Elem().Interface())
+	}
+	return results, nil
+}
+
+// QueryResources returns the resources of the given type whose properties have the values in filter.
+func (rm *ResourceManager) QueryResources(ctx context.Context, resourceType string, filter map[string]string) ([]map[string]interface{}, error) {
+	endpointUrl, authType, err := rm.getResourceEndpoint(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0)
+	if err = rm.query(ctx, endpointUrl, authType, filter, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Get returns the resource of the type of t with the given id as a value of type t, or nil if it does not exist.
+func (rm *ResourceManager) Get(ctx context.Context, t reflect.Type, id string) (interface{}, error) {
+	result := reflect.New(t)
+	found, err := rm.get(ctx, t.Name(), id, result.Interface())
+	if err != nil || !found {
+		return nil, err
+	}
+	return result.Elem().Interface(), nil
+}
+
+// GetResource returns the resource of the given type with the given id, or nil if it does not exist.
+func (rm *ResourceManager) GetResource(ctx context.Context, resourceType string, id string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	found, err := rm.get(ctx, resourceType, id, &result)
+	if err != nil || !found {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Create creates a resource, either a map with an @type or one of the types of mcmamodel, and returns the created
+// resource with the same type.
+func (rm *ResourceManager) Create(ctx context.Context, resource interface{}) (interface{}, error) {
+	resourceType, err := getResourceType(resource)
+	if err != nil {
+		return nil, err
+	}
+	endpointUrl, authType, err := rm.getResourceEndpoint(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := reflect.New(reflect.TypeOf(resource))
+	found, err := rm.send(ctx, http.MethodPost, endpointUrl, authType, resource, result.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("POST %s failed with status 404 Not Found", endpointUrl)
+	}
+	return result.Elem().Interface(), nil
+}
+
+// Update replaces a resource, either a map with an @type and id or one of the types of mcmamodel, and returns the
+// updated resource with the same type.
+func (rm *ResourceManager) Update(ctx context.Context, resource interface{}) (interface{}, error) {
+	resourceType, err := getResourceType(resource)
+	if err != nil {
+		return nil, err
+	}
+	id, err := getResourceId(resource)
+	if err != nil {
+		return nil, err
+	}
+	_, authType, err := rm.getResourceEndpoint(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := reflect.New(reflect.TypeOf(resource))
+	found, err := rm.send(ctx, http.MethodPut, id, authType, resource, result.Interface())
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s with id %s not found", resourceType, id)
+	}
+	return result.Elem().Interface(), nil
+}
+
+// Delete deletes the resource of the type of t with the given id. Deleting a resource that does not exist succeeds.
+func (rm *ResourceManager) Delete(ctx context.Context, t reflect.Type, id string) error {
+	return rm.DeleteResource(ctx, t.Name(), id)
+}
+
+// DeleteResource deletes the resource of the given type with the given id. Deleting a resource that does not exist
+// succeeds.
+func (rm *ResourceManager) DeleteResource(ctx context.Context, resourceType string, id string) error {
+	_, authType, err := rm.getResourceEndpoint(ctx, resourceType)
+	if err != nil {
+		return err
+	}
+	_, err = rm.send(ctx, http.MethodDelete, id, authType, nil, nil)
+	return err
+}
+
+// getResourceEndpoint returns the url of the endpoint serving the given resource type, and its auth type, which is
+// the auth type of its service unless the endpoint has its own. Services are served by the services endpoint of the
+// registry, other types by the endpoints listed in its services.
+func (rm *ResourceManager) getResourceEndpoint(ctx context.Context, resourceType string) (string, string, error) {
+	if resourceType == "Service" {
+		return rm.servicesUrl, rm.servicesAuthType, nil
+	}
+
+	var services []mcmamodel.Service
+	if err := rm.query(ctx, rm.servicesUrl, rm.servicesAuthType, nil, &services); err != nil {
+		return "", "", err
+	}
+	for _, service := range services {
+		for _, resourceEndpoint := range service.Resources {
+			if resourceEndpoint.ResourceType != resourceType {
+				continue
+			}
+			authType := resourceEndpoint.AuthType
+			if authType == "" {
+				authType = service.AuthType
+			}
+			return resourceEndpoint.HttpEndpoint, authType, nil
+		}
+	}
+	return "", "", fmt.Errorf("no resource endpoint registered for resource type %s", resourceType)
+}
+
+// query gets the resources of the collection at endpointUrl that match filter into results, which must be a pointer to
+// a slice. Registries return either the array of results or an object holding it in its results property.
+func (rm *ResourceManager) query(ctx context.Context, endpointUrl string, authType string, filter map[string]string, results interface{}) error {
+	queryUrl, err := url.Parse(endpointUrl)
+	if err != nil {
+		return err
+	}
+	if len(filter) > 0 {
+		query := queryUrl.Query()
+		for key, value := range filter {
+			query.Set(key, value)
+		}
+		queryUrl.RawQuery = query.Encode()
+	}
+
+	var body json.RawMessage
+	if _, err = rm.send(ctx, http.MethodGet, queryUrl.String(), authType, nil, &body); err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var queryResults struct {
+			Results json.RawMessage `json:"results"`
+		}
+		if err = json.Unmarshal(body, &queryResults); err != nil {
+			return fmt.Errorf("error decoding query results from %s: %s", endpointUrl, err)
+		}
+		body = queryResults.Results
+	}
+	if len(body) == 0 || string(body) == "null" {
+		return nil
+	}
+	if err = json.Unmarshal(body, results); err != nil {
+		return fmt.Errorf("error decoding query results from %s: %s", endpointUrl, err)
+	}
+	return nil
+}
+
+// get gets the resource of the given type with the given id into result. Returns false if it does not exist.
+func (rm *ResourceManager) get(ctx context.Context, resourceType string, id string, result interface{}) (bool, error) {
+	_, authType, err := rm.getResourceEndpoint(ctx, resourceType)
+	if err != nil {
+		return false, err
+	}
+	return rm.send(ctx, http.MethodGet, id, authType, nil, result)
+}
+
+// send sends a request with the given body encoded as JSON, authenticated for the given auth type, and decodes the
+// response into result if it is not nil. Returns false if the server responded with 404 Not Found, which is not an
+// error.
+func (rm *ResourceManager) send(ctx context.Context, method string, requestUrl string, authType string, body interface{}, result interface{}) (bool, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return false, err
+		}
+		bodyReader = bytes.NewReader(bodyBytes)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestUrl, bodyReader)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if authType != "" {
+		authenticator, found := rm.authenticators[authType]
+		if !found {
+			return false, fmt.Errorf("no authenticator configured for auth type %s", authType)
+		}
+		if err = authenticator.Authenticate(request); err != nil {
+			return false, err
+		}
+	}
+
+	response, err := rm.httpClient.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return false, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if response.StatusCode >= 300 {
+		return false, fmt.Errorf("%s %s failed with status %s: %s", method, requestUrl, response.Status, strings.TrimSpace(string(responseBody)))
+	}
+
+	if result != nil && len(bytes.TrimSpace(responseBody)) > 0 {
+		if err = json.Unmarshal(responseBody, result); err != nil {
+			return false, fmt.Errorf("error decoding response of %s %s: %s", method, requestUrl, err)
+		}
+	}
+	return true, nil
+}
+
+// getResourceType returns the @type of a resource map, or the name of the type of a typed resource.
+func getResourceType(resource interface{}) (string, error) {
+	if resourceMap, ok := resource.(map[string]interface{}); ok {
+		resourceType, _ := resourceMap["@type"].(string)
+		if resourceType == "" {
+			return "", fmt.Errorf("resource has no @type")
+		}
+		return resourceType, nil
+	}
+	return reflect.TypeOf(resource).Name(), nil
+}
+
+// getResourceId returns the id of a resource map or typed resource.
+func getResourceId(resource interface{}) (string, error) {
+	var id string
+	if resourceMap, ok := resource.(map[string]interface{}); ok {
+		id, _ = resourceMap["id"].(string)
+	} else if v := reflect.ValueOf(resource); v.Kind() == reflect.Struct {
+		if idField := v.FieldByName("Id"); idField.IsValid() && idField.Kind() == reflect.String {
+			id = idField.String()
+		}
+	}
+	if id == "" {
+		return "", fmt.Errorf("resource has no id")
+	}
+	return id, nil
+}
@@ -0,0 +1,125 @@
+package mcmaclient
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+	"github.com/ebu/terraform-provider-mcma/mcmatest"
+)
+
+func TestResourceManager_typedResources(t *testing.T) {
+	r := mcmatest.NewRegistry(mcmatest.AuthTypeMcmaApiKey)
+	defer r.Close()
+
+	ctx := context.Background()
+	rm := NewResourceManager(r.URL(), mcmatest.AuthTypeMcmaApiKey)
+	rm.AddAuth(mcmatest.AuthTypeMcmaApiKey, NewMcmaApiKeyAuthenticator(mcmatest.McmaApiKey))
+
+	created, err := rm.Create(ctx, mcmamodel.JobProfile{
+		Type:            "JobProfile",
+		Name:            "ExtractThumbnail",
+		InputParameters: []mcmamodel.JobParameter{{ParameterName: "inputFile", ParameterType: "Locator"}},
+	})
+	if err != nil {
+		t.Fatalf("error creating job profile: %s", err)
+	}
+	jobProfile := created.(mcmamodel.JobProfile)
+	if jobProfile.Id == "" || jobProfile.DateCreated.IsZero() {
+		t.Fatalf("expected created job profile to have an id and creation date, got %+v", jobProfile)
+	}
+
+	results, err := rm.Query(ctx, reflect.TypeOf(mcmamodel.JobProfile{}), map[string]string{"name": "ExtractThumbnail"})
+	if err != nil {
+		t.Fatalf("error querying job profiles: %s", err)
+	}
+	if len(results) != 1 || results[0].(mcmamodel.JobProfile).Id != jobProfile.Id {
+		t.Fatalf("expected query to return the created job profile, got %+v", results)
+	}
+
+	jobProfile.InputParameters = append(jobProfile.InputParameters, mcmamodel.JobParameter{ParameterName: "width", ParameterType: "number"})
+	if _, err = rm.Update(ctx, jobProfile); err != nil {
+		t.Fatalf("error updating job profile: %s", err)
+	}
+	resource, err := rm.Get(ctx, reflect.TypeOf(mcmamodel.JobProfile{}), jobProfile.Id)
+	if err != nil {
+		t.Fatalf("error getting job profile: %s", err)
+	}
+	if parameters := resource.(mcmamodel.JobProfile).InputParameters; len(parameters) != 2 {
+		t.Fatalf("expected updated job profile to have 2 input parameters, got %+v", parameters)
+	}
+
+	if err = rm.Delete(ctx, reflect.TypeOf(mcmamodel.JobProfile{}), jobProfile.Id); err != nil {
+		t.Fatalf("error deleting job profile: %s", err)
+	}
+	if resource, err = rm.Get(ctx, reflect.TypeOf(mcmamodel.JobProfile{}), jobProfile.Id); err != nil || resource != nil {
+		t.Fatalf("expected deleted job profile not to be found, got %+v (%v)", resource, err)
+	}
+	if err = rm.Delete(ctx, reflect.TypeOf(mcmamodel.JobProfile{}), jobProfile.Id); err != nil {
+		t.Fatalf("expected deleting a job profile that does not exist to succeed, got %s", err)
+	}
+}
+
+func TestResourceManager_untypedResources(t *testing.T) {
+	r := mcmatest.NewRegistry(mcmatest.AuthTypeNone)
+	defer r.Close()
+	r.AddResourceEndpoint("BMContent", "/api/bm-contents")
+
+	ctx := context.Background()
+	rm := NewResourceManagerNoAuth(r.URL())
+
+	created, err := rm.Create(ctx, map[string]interface{}{"@type": "BMContent", "title": "Big Buck Bunny"})
+	if err != nil {
+		t.Fatalf("error creating resource: %s", err)
+	}
+	id := created.(map[string]interface{})["id"].(string)
+	if !strings.HasPrefix(id, r.URL()+"bm-contents/") {
+		t.Fatalf("expected resource to be created in the registered endpoint, got id '%s'", id)
+	}
+
+	results, err := rm.QueryResources(ctx, "BMContent", map[string]string{"title": "Big Buck Bunny"})
+	if err != nil {
+		t.Fatalf("error querying resources: %s", err)
+	}
+	if len(results) != 1 || results[0]["id"] != id {
+		t.Fatalf("expected query to return the created resource, got %v", results)
+	}
+
+	resource, err := rm.GetResource(ctx, "BMContent", id)
+	if err != nil || resource == nil || resource["title"] != "Big Buck Bunny" {
+		t.Fatalf("expected to get the created resource, got %v (%v)", resource, err)
+	}
+
+	if _, err = rm.QueryResources(ctx, "BMEssence", nil); err == nil || !strings.Contains(err.Error(), "BMEssence") {
+		t.Fatalf("expected error naming the resource type without an endpoint, got %v", err)
+	}
+}
+
+func TestResourceManager_authentication(t *testing.T) {
+	r := mcmatest.NewRegistry(mcmatest.AuthTypeAWS4)
+	defer r.Close()
+
+	ctx := context.Background()
+	rm := NewResourceManager(r.URL(), mcmatest.AuthTypeAWS4)
+	if _, err := rm.QueryResources(ctx, "Service", nil); err == nil || !strings.Contains(err.Error(), "no authenticator") {
+		t.Fatalf("expected error for missing authenticator, got %v", err)
+	}
+
+	rm.AddAuth(mcmatest.AuthTypeAWS4, NewAWS4Authenticator(credentials.NewStaticCredentials("AKIAWRONGKEY", "wrongsecretkey", ""), mcmatest.AwsRegion))
+	if _, err := rm.QueryResources(ctx, "Service", nil); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected request signed with wrong credentials to be rejected, got %v", err)
+	}
+
+	rm.AddAuth(mcmatest.AuthTypeAWS4, NewAWS4Authenticator(credentials.NewStaticCredentials(mcmatest.AwsAccessKey, mcmatest.AwsSecretKey, ""), mcmatest.AwsRegion))
+	created, err := rm.Create(ctx, mcmamodel.JobProfile{Type: "JobProfile", Name: "Transcode"})
+	if err != nil {
+		t.Fatalf("error creating job profile with signed request: %s", err)
+	}
+	if created.(mcmamodel.JobProfile).Name != "Transcode" {
+		t.Fatalf("expected signed request body to be sent, got %+v", created)
+	}
+}
@@ -0,0 +1,48 @@
+// Package mcmamodel holds the MCMA resources that the provider reads and writes as typed documents.
+package mcmamodel
+
+import "time"
+
+// Service is a service registered in the MCMA service registry, with the endpoints of the resources it serves.
+type Service struct {
+	Type          string             `json:"@type"`
+	Id            string             `json:"id,omitempty"`
+	DateCreated   time.Time          `json:"dateCreated,omitempty"`
+	DateModified  time.Time          `json:"dateModified,omitempty"`
+	Name          string             `json:"name"`
+	AuthType      string             `json:"authType,omitempty"`
+	JobType       string             `json:"jobType,omitempty"`
+	JobProfileIds []string           `json:"jobProfileIds,omitempty"`
+	Resources     []ResourceEndpoint `json:"resources"`
+}
+
+// ResourceEndpoint is the url at which a service serves the resources of a type. An empty AuthType means that the
+// auth type of the service is used.
+type ResourceEndpoint struct {
+	Type         string    `json:"@type"`
+	Id           string    `json:"id,omitempty"`
+	DateCreated  time.Time `json:"dateCreated,omitempty"`
+	DateModified time.Time `json:"dateModified,omitempty"`
+	ResourceType string    `json:"resourceType"`
+	HttpEndpoint string    `json:"httpEndpoint"`
+	AuthType     string    `json:"authType,omitempty"`
+}
+
+// JobParameter is an input or output parameter of a job profile.
+type JobParameter struct {
+	ParameterName string `json:"parameterName"`
+	ParameterType string `json:"parameterType"`
+}
+
+// JobProfile is a job profile registered in the MCMA service registry.
+type JobProfile struct {
+	Type                    string                 `json:"@type"`
+	Id                      string                 `json:"id,omitempty"`
+	DateCreated             time.Time              `json:"dateCreated,omitempty"`
+	DateModified            time.Time              `json:"dateModified,omitempty"`
+	Name                    string                 `json:"name"`
+	InputParameters         []JobParameter         `json:"inputParameters,omitempty"`
+	OutputParameters        []JobParameter         `json:"outputParameters,omitempty"`
+	OptionalInputParameters []JobParameter         `json:"optionalInputParameters,omitempty"`
+	Custom                  map[string]interface{} `json:"custom,omitempty"`
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 )
 
@@ -13,9 +14,10 @@ func GetAuthDataString(authData map[string]interface{}, key string, required boo
 		if required {
 			return "", diag.Diagnostics{
 				diag.Diagnostic{
-					Severity: diag.Error,
-					Summary:  fmt.Sprintf("'%s' not specified in auth data", key),
-					Detail:   fmt.Sprintf("A property with name '%s' must be specified for this authentication type", key),
+					Severity:      diag.Error,
+					Summary:       fmt.Sprintf("'%s' not specified in auth data", key),
+					Detail:        fmt.Sprintf("A property with name '%s' must be specified for this authentication type", key),
+					AttributePath: cty.GetAttrPath(key),
 				},
 			}
 		} else {
@@ -29,9 +31,10 @@ func GetAuthDataString(authData map[string]interface{}, key string, required boo
 		default:
 			return "", diag.Diagnostics{
 				diag.Diagnostic{
-					Severity: diag.Error,
-					Summary:  fmt.Sprintf("'%s' be a string", key),
-					Detail:   fmt.Sprintf("Expected a string value for '%s' in auth data but got a value of type", reflect.TypeOf(v).String()),
+					Severity:      diag.Error,
+					Summary:       fmt.Sprintf("'%s' must be a string", key),
+					Detail:        fmt.Sprintf("Expected a string value for '%s' in auth data but got a value of type %s", key, reflect.TypeOf(v).String()),
+					AttributePath: cty.GetAttrPath(key),
 				},
 			}
 		}
@@ -39,3 +42,27 @@ func GetAuthDataString(authData map[string]interface{}, key string, required boo
 		return value, nil
 	}
 }
+
+// authDataError returns an error diagnostic for the attribute with the given key in the data of an auth block.
+func authDataError(key string, summary string, detail string) diag.Diagnostics {
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       summary,
+			Detail:        detail,
+			AttributePath: cty.GetAttrPath(key),
+		},
+	}
+}
+
+// withAttributePathPrefix prefixes the attribute paths of diagnostics returned for the data of a nested block
+// with the path to that block. Diagnostics without an attribute path are attributed to the block itself.
+func withAttributePathPrefix(diags diag.Diagnostics, prefix cty.Path) diag.Diagnostics {
+	for i := range diags {
+		attributePath := make(cty.Path, 0, len(prefix)+len(diags[i].AttributePath))
+		attributePath = append(attributePath, prefix...)
+		attributePath = append(attributePath, diags[i].AttributePath...)
+		diags[i].AttributePath = attributePath
+	}
+	return diags
+}
@@ -1,14 +1,124 @@
 package mcma
 
 import (
+	"fmt"
+	"net/http"
 	"os"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
-	mcmaclient "github.com/ebu/mcma-libraries-go/client"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
 )
 
-func GetAWS4Authenticator(authData map[string]interface{}) (mcmaclient.Authenticator, diag.Diagnostics) {
+// aws4AuthEnvVars maps the attributes of an aws4_auth block to the environment variables that configure the block when
+// none is specified in the provider configuration.
+var aws4AuthEnvVars = map[string]string{
+	"region":        "MCMA_AWS_REGION",
+	"profile":       "MCMA_AWS_PROFILE",
+	"access_key":    "MCMA_AWS_ACCESS_KEY",
+	"secret_key":    "MCMA_AWS_SECRET_KEY",
+	"session_token": "MCMA_AWS_SESSION_TOKEN",
+}
+
+func aws4AuthResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"auth_type": {
+				Type:        schema.TypeString,
+				Description: "The auth type under which the authenticator is registered. Services and resource endpoints with this auth type will use it. Use different auth types to configure several aws4_auth blocks, e.g. one per AWS account.",
+				Optional:    true,
+				Default:     "AWS4",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Description: "The AWS region to use for authentication. Set from the MCMA_AWS_REGION environment variable when no aws4_auth block is specified.",
+				Optional:    true,
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Description: "The AWS profile to use for authentication. Set from the MCMA_AWS_PROFILE environment variable when no aws4_auth block is specified.",
+				Optional:    true,
+			},
+			"access_key": {
+				Type:        schema.TypeString,
+				Description: "The AWS access key to use for authentication. Requires that secret_key also be specified. Set from the MCMA_AWS_ACCESS_KEY environment variable when no aws4_auth block is specified.",
+				Optional:    true,
+			},
+			"secret_key": {
+				Type:        schema.TypeString,
+				Description: "The AWS secret key to use for authentication. Requires that access_key also be specified. Set from the MCMA_AWS_SECRET_KEY environment variable when no aws4_auth block is specified.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"session_token": {
+				Type:        schema.TypeString,
+				Description: "The AWS session token to use for authentication with temporary credentials. Only used when access_key and secret_key are specified. Set from the MCMA_AWS_SESSION_TOKEN environment variable when no aws4_auth block is specified.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"assume_role": {
+				Type:        schema.TypeList,
+				Description: "An IAM role to assume with STS before authenticating. The credentials specified in this block (keys, profile or environment) are used to call STS.",
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role_arn": {
+							Type:        schema.TypeString,
+							Description: "The ARN of the role to assume.",
+							Required:    true,
+						},
+						"session_name": {
+							Type:        schema.TypeString,
+							Description: "The session name to use when assuming the role. Defaults to 'terraform-provider-mcma'.",
+							Optional:    true,
+						},
+						"external_id": {
+							Type:        schema.TypeString,
+							Description: "The external ID to use when assuming the role.",
+							Optional:    true,
+						},
+						"duration": {
+							Type:             schema.TypeString,
+							Description:      "The duration of the role session, e.g. '1h' or '30m'. Defaults to the STS default of 1 hour.",
+							Optional:         true,
+							ValidateDiagFunc: validateDuration,
+						},
+						"sts_endpoint": {
+							Type:        schema.TypeString,
+							Description: "A custom url for the STS endpoint, e.g. for a VPC endpoint.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateDuration(v interface{}, path cty.Path) diag.Diagnostics {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		return diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid duration.",
+				Detail:        fmt.Sprintf("invalid duration '%s': %s", v, err),
+				AttributePath: path,
+			},
+		}
+	}
+	return nil
+}
+
+func GetAWS4Authenticator(authData map[string]interface{}, httpClient *http.Client) (mcmaclient.Authenticator, diag.Diagnostics) {
 	region, d := GetAuthDataString(authData, "region", false)
 	if d != nil {
 		return nil, d
@@ -17,7 +127,7 @@ func GetAWS4Authenticator(authData map[string]interface{}) (mcmaclient.Authentic
 	if region == "" {
 		region = os.Getenv("AWS_REGION")
 		if region == "" {
-			return nil, diag.Errorf("region not specified and AWS_REGION environment variable not set")
+			return nil, authDataError("region", "AWS region not specified.", "region not specified and neither MCMA_AWS_REGION nor AWS_REGION environment variable set")
 		}
 	}
 
@@ -25,26 +135,135 @@ func GetAWS4Authenticator(authData map[string]interface{}) (mcmaclient.Authentic
 	if d != nil {
 		return nil, d
 	}
+	secretKey, d := GetAuthDataString(authData, "secret_key", false)
+	if d != nil {
+		return nil, d
+	}
+	sessionToken, d := GetAuthDataString(authData, "session_token", false)
+	if d != nil {
+		return nil, d
+	}
+	profile, d := GetAuthDataString(authData, "profile", false)
+	if d != nil {
+		return nil, d
+	}
 
-	if len(accessKey) > 0 {
-		secretKey, d := GetAuthDataString(authData, "secret_key", true)
-		if d != nil {
-			return nil, d
+	if d := validateAWS4AuthData(accessKey, secretKey, sessionToken, profile); d != nil {
+		return nil, d
+	}
+
+	if assumeRoleData := getAssumeRoleData(authData); assumeRoleData != nil {
+		sess, err := newAWSSession(region, accessKey, secretKey, sessionToken, profile, httpClient)
+		if err != nil {
+			return nil, authDataError("assume_role", "Failed to create AWS session.", err.Error())
 		}
-		sessionToken, d := GetAuthDataString(authData, "session_token", false)
+		creds, d := assumeRole(sess, assumeRoleData)
 		if d != nil {
-			return nil, d
+			return nil, withAttributePathPrefix(d, cty.GetAttrPath("assume_role").IndexInt(0))
 		}
-		return mcmaclient.NewAWS4AuthenticatorFromKeys(accessKey, secretKey, sessionToken, region), nil
+		return mcmaclient.NewAWS4Authenticator(creds, region), nil
 	}
 
-	profile, d := GetAuthDataString(authData, "profile", false)
+	var creds *credentials.Credentials
+	if len(accessKey) > 0 {
+		creds = credentials.NewStaticCredentials(accessKey, secretKey, sessionToken)
+	} else if len(profile) > 0 {
+		creds = credentials.NewSharedCredentials("", profile)
+	} else {
+		creds = credentials.NewEnvCredentials()
+	}
+	return mcmaclient.NewAWS4Authenticator(creds, region), nil
+}
+
+func validateAWS4AuthData(accessKey, secretKey, sessionToken, profile string) diag.Diagnostics {
+	if accessKey != "" && secretKey == "" {
+		return authDataError("secret_key", "AWS secret key not specified.", "secret_key must be specified when access_key is specified")
+	}
+	if secretKey != "" && accessKey == "" {
+		return authDataError("access_key", "AWS access key not specified.", "access_key must be specified when secret_key is specified")
+	}
+	if sessionToken != "" && accessKey == "" {
+		return authDataError("session_token", "AWS session token specified without keys.", "session_token can only be used together with access_key and secret_key")
+	}
+	if accessKey != "" && profile != "" {
+		return authDataError("profile", "Conflicting AWS credentials.", "profile cannot be used together with access_key and secret_key. When the block is configured from environment variables, MCMA_AWS_PROFILE cannot be set together with MCMA_AWS_ACCESS_KEY.")
+	}
+	return nil
+}
+
+func getAssumeRoleData(authData map[string]interface{}) map[string]interface{} {
+	blocks, ok := authData["assume_role"].([]interface{})
+	if !ok || len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	return blocks[0].(map[string]interface{})
+}
+
+func newAWSSession(region, accessKey, secretKey, sessionToken, profile string, httpClient *http.Client) (*session.Session, error) {
+	config := aws.NewConfig().WithRegion(region).WithHTTPClient(httpClient)
+	if len(accessKey) > 0 {
+		config = config.WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, sessionToken))
+	}
+	return session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		Profile:           profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+}
+
+// assumeRole returns the credentials of a role session, which assume the role again with STS before they expire so
+// that long applies and job waits keep working. The role is assumed straight away, so that errors are reported when
+// the provider is configured.
+func assumeRole(sess *session.Session, assumeRoleData map[string]interface{}) (*credentials.Credentials, diag.Diagnostics) {
+	roleArn, d := GetAuthDataString(assumeRoleData, "role_arn", true)
+	if d != nil {
+		return nil, d
+	}
+	sessionName, d := GetAuthDataString(assumeRoleData, "session_name", false)
 	if d != nil {
 		return nil, d
 	}
-	if len(profile) > 0 {
-		return mcmaclient.NewAWS4AuthenticatorFromProfile(profile, region), nil
+	if sessionName == "" {
+		sessionName = "terraform-provider-mcma"
+	}
+	externalId, d := GetAuthDataString(assumeRoleData, "external_id", false)
+	if d != nil {
+		return nil, d
+	}
+	duration, d := GetAuthDataString(assumeRoleData, "duration", false)
+	if d != nil {
+		return nil, d
+	}
+	stsEndpoint, d := GetAuthDataString(assumeRoleData, "sts_endpoint", false)
+	if d != nil {
+		return nil, d
+	}
+
+	var parsedDuration time.Duration
+	if duration != "" {
+		var err error
+		if parsedDuration, err = time.ParseDuration(duration); err != nil {
+			return nil, authDataError("duration", "Invalid duration.", fmt.Sprintf("invalid duration '%s': %s", duration, err))
+		}
+	}
+
+	config := aws.NewConfig()
+	if stsEndpoint != "" {
+		config = config.WithEndpoint(stsEndpoint)
+	}
+
+	creds := stscreds.NewCredentialsWithClient(sts.New(sess, config), roleArn, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = sessionName
+		if externalId != "" {
+			p.ExternalID = aws.String(externalId)
+		}
+		if parsedDuration > 0 {
+			p.Duration = parsedDuration
+		}
+	})
+	if _, err := creds.Get(); err != nil {
+		return nil, authDataError("role_arn", fmt.Sprintf("Failed to assume role %s", roleArn), err.Error())
 	}
 
-	return mcmaclient.NewAWS4AuthenticatorFromEnvVars(), nil
+	return creds, nil
 }
@@ -0,0 +1,145 @@
+package mcma
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+)
+
+const testAssumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIATESTACCESSKEY</AccessKeyId>
+      <SecretAccessKey>testsecretkey</SecretAccessKey>
+      <SessionToken>testsessiontoken</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/deployer/terraform-provider-mcma</Arn>
+      <AssumedRoleId>AROATESTROLEID:terraform-provider-mcma</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata>
+    <RequestId>01234567-89ab-cdef-0123-456789abcdef</RequestId>
+  </ResponseMetadata>
+</AssumeRoleResponse>`
+
+func TestAssumeRole(t *testing.T) {
+	var form map[string]string
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("error parsing STS request: %s", err)
+		}
+		form = make(map[string]string)
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(testAssumeRoleResponse))
+	}))
+	defer sts.Close()
+
+	sess, err := newAWSSession("us-east-1", "AKIATESTACCESSKEY", "basesecretkey", "", "", http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error creating session: %s", err)
+	}
+
+	creds, d := assumeRole(sess, map[string]interface{}{
+		"role_arn":     "arn:aws:iam::123456789012:role/deployer",
+		"external_id":  "ext-1234",
+		"duration":     "30m",
+		"sts_endpoint": sts.URL,
+	})
+	if d != nil {
+		t.Fatalf("unexpected error assuming role: %v", d)
+	}
+
+	expectedForm := map[string]string{
+		"Action":          "AssumeRole",
+		"RoleArn":         "arn:aws:iam::123456789012:role/deployer",
+		"RoleSessionName": "terraform-provider-mcma",
+		"ExternalId":      "ext-1234",
+		"DurationSeconds": "1800",
+	}
+	for k, v := range expectedForm {
+		if form[k] != v {
+			t.Errorf("expected STS request parameter %s to be '%s', got '%s'", k, v, form[k])
+		}
+	}
+
+	value, err := creds.Get()
+	if err != nil {
+		t.Fatalf("error getting credentials: %s", err)
+	}
+	if value.AccessKeyID != "ASIATESTACCESSKEY" || value.SecretAccessKey != "testsecretkey" || value.SessionToken != "testsessiontoken" {
+		t.Errorf("unexpected credentials returned from STS: %v", value)
+	}
+}
+
+func TestAssumeRole_error(t *testing.T) {
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code><Message>not authorized</Message></Error></ErrorResponse>`))
+	}))
+	defer sts.Close()
+
+	sess, err := newAWSSession("us-east-1", "AKIATESTACCESSKEY", "basesecretkey", "", "", http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error creating session: %s", err)
+	}
+
+	_, d := assumeRole(sess, map[string]interface{}{
+		"role_arn":     "arn:aws:iam::123456789012:role/deployer",
+		"sts_endpoint": sts.URL,
+	})
+	if !d.HasError() {
+		t.Fatal("expected error when STS denies the request")
+	}
+}
+
+func TestAssumeRole_refresh(t *testing.T) {
+	calls := 0
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "text/xml")
+		// credentials that have already expired are assumed again on every use
+		_, _ = w.Write([]byte(strings.Replace(testAssumeRoleResponse, "2030-01-01T00:00:00Z", "2000-01-01T00:00:00Z", 1)))
+	}))
+	defer sts.Close()
+
+	sess, err := newAWSSession("us-east-1", "AKIATESTACCESSKEY", "basesecretkey", "", "", http.DefaultClient)
+	if err != nil {
+		t.Fatalf("error creating session: %s", err)
+	}
+
+	creds, d := assumeRole(sess, map[string]interface{}{
+		"role_arn":     "arn:aws:iam::123456789012:role/deployer",
+		"sts_endpoint": sts.URL,
+	})
+	if d != nil {
+		t.Fatalf("unexpected error assuming role: %v", d)
+	}
+	if calls != 1 {
+		t.Fatalf("expected role to be assumed when configuring, got %d STS calls", calls)
+	}
+
+	authenticator := mcmaclient.NewAWS4Authenticator(creds, "us-east-1")
+	request, _ := http.NewRequest(http.MethodPost, "https://service.example.com/api/jobs", strings.NewReader(`{"@type":"AmeJob"}`))
+	if err = authenticator.Authenticate(request); err != nil {
+		t.Fatalf("error authenticating request: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected expired credentials to be refreshed, got %d STS calls", calls)
+	}
+	if authorization := request.Header.Get("Authorization"); !strings.Contains(authorization, "Credential=ASIATESTACCESSKEY/") {
+		t.Errorf("expected request to be signed with the role credentials, got '%s'", authorization)
+	}
+	if body, _ := io.ReadAll(request.Body); string(body) != `{"@type":"AmeJob"}` {
+		t.Errorf("expected request body to be kept, got '%s'", body)
+	}
+}
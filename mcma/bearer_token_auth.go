@@ -0,0 +1,121 @@
+package mcma
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+)
+
+// bearerTokenAuthEnvVars configure a bearer_token_auth block when none is specified.
+var bearerTokenAuthEnvVars = map[string]string{
+	"token":      "MCMA_BEARER_TOKEN",
+	"token_file": "MCMA_BEARER_TOKEN_FILE",
+}
+
+func bearerTokenAuthResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"auth_type": {
+				Type:        schema.TypeString,
+				Description: "The auth type under which the authenticator is registered. Services and resource endpoints with this auth type will use it. Use different auth types to configure several bearer_token_auth blocks.",
+				Optional:    true,
+				Default:     "JWT",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Description: "The bearer token to send in the Authorization header. Ignored if token_file or token_env_var is specified. If none of token, token_file and token_env_var is specified, the token is read from the file set in the MCMA_BEARER_TOKEN_FILE environment variable or, if it is not set, taken from the MCMA_BEARER_TOKEN environment variable.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_file": {
+				Type:        schema.TypeString,
+				Description: "The path to a file containing the bearer token. The file is read again for every request, so the token can be rotated while the provider is running.",
+				Optional:    true,
+			},
+			"token_env_var": {
+				Type:        schema.TypeString,
+				Description: "The name of an environment variable containing the bearer token. The variable is read again for every request. Ignored if token_file is specified.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+type bearerTokenAuthenticator struct {
+	getToken func() (string, error)
+}
+
+func (a *bearerTokenAuthenticator) Authenticate(request *http.Request) error {
+	token, err := a.getToken()
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// GetBearerTokenAuthenticator returns an authenticator for the token set in token_file, token_env_var or token, in
+// that order. The MCMA_BEARER_TOKEN_FILE and MCMA_BEARER_TOKEN environment variables are only read when none of them is
+// set, so that they never override the configuration.
+func GetBearerTokenAuthenticator(authData map[string]interface{}) (mcmaclient.Authenticator, diag.Diagnostics) {
+	tokenFile, d := GetAuthDataString(authData, "token_file", false)
+	if d != nil {
+		return nil, d
+	}
+	tokenEnvVar, d := GetAuthDataString(authData, "token_env_var", false)
+	if d != nil {
+		return nil, d
+	}
+	token, d := GetAuthDataString(authData, "token", false)
+	if d != nil {
+		return nil, d
+	}
+	if tokenFile == "" && tokenEnvVar == "" && token == "" {
+		if tokenFile = os.Getenv("MCMA_BEARER_TOKEN_FILE"); tokenFile == "" {
+			token = os.Getenv("MCMA_BEARER_TOKEN")
+		}
+	}
+
+	if tokenFile != "" {
+		return &bearerTokenAuthenticator{
+			getToken: func() (string, error) {
+				tokenBytes, err := os.ReadFile(tokenFile)
+				if err != nil {
+					return "", fmt.Errorf("error reading bearer token from file %s: %v", tokenFile, err)
+				}
+				token := strings.TrimSpace(string(tokenBytes))
+				if token == "" {
+					return "", fmt.Errorf("bearer token file %s is empty", tokenFile)
+				}
+				return token, nil
+			},
+		}, nil
+	}
+
+	if tokenEnvVar != "" {
+		return &bearerTokenAuthenticator{
+			getToken: func() (string, error) {
+				token := os.Getenv(tokenEnvVar)
+				if token == "" {
+					return "", fmt.Errorf("bearer token environment variable %s is not set", tokenEnvVar)
+				}
+				return token, nil
+			},
+		}, nil
+	}
+
+	if token == "" {
+		return nil, authDataError("token", "Bearer token not specified.", "none of token, token_file or token_env_var specified and neither MCMA_BEARER_TOKEN nor MCMA_BEARER_TOKEN_FILE environment variable set")
+	}
+	return &bearerTokenAuthenticator{
+		getToken: func() (string, error) {
+			return token, nil
+		},
+	}, nil
+}
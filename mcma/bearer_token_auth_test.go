@@ -0,0 +1,118 @@
+package mcma
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+)
+
+func testBearerTokenAuthenticate(t *testing.T, authenticator mcmaclient.Authenticator, expectedToken string) {
+	request, _ := http.NewRequest(http.MethodGet, "https://service.example.com/api/jobs", nil)
+	if err := authenticator.Authenticate(request); err != nil {
+		t.Fatalf("unexpected error authenticating request: %s", err)
+	}
+	if header := request.Header.Get("Authorization"); header != "Bearer "+expectedToken {
+		t.Fatalf("expected Authorization header 'Bearer %s', got '%s'", expectedToken, header)
+	}
+}
+
+func TestBearerTokenAuthenticator_token(t *testing.T) {
+	authenticator, d := GetBearerTokenAuthenticator(map[string]interface{}{
+		"token": "inline-token",
+	})
+	if d != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", d)
+	}
+	testBearerTokenAuthenticate(t, authenticator, "inline-token")
+}
+
+func TestBearerTokenAuthenticator_tokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, d := GetBearerTokenAuthenticator(map[string]interface{}{
+		"token":      "inline-token",
+		"token_file": tokenFile,
+	})
+	if d != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", d)
+	}
+	testBearerTokenAuthenticate(t, authenticator, "file-token-1")
+
+	if err := os.WriteFile(tokenFile, []byte("file-token-2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	testBearerTokenAuthenticate(t, authenticator, "file-token-2")
+}
+
+func TestBearerTokenAuthenticator_tokenEnvVar(t *testing.T) {
+	t.Setenv("TEST_MCMA_BEARER_TOKEN", "env-token")
+
+	authenticator, d := GetBearerTokenAuthenticator(map[string]interface{}{
+		"token_env_var": "TEST_MCMA_BEARER_TOKEN",
+	})
+	if d != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", d)
+	}
+	testBearerTokenAuthenticate(t, authenticator, "env-token")
+}
+
+func TestBearerTokenAuthenticator_envVars(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MCMA_BEARER_TOKEN_FILE", tokenFile)
+	t.Setenv("MCMA_BEARER_TOKEN", "env-token")
+
+	authenticator, d := GetBearerTokenAuthenticator(map[string]interface{}{"auth_type": "JWT"})
+	if d != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", d)
+	}
+	testBearerTokenAuthenticate(t, authenticator, "file-token")
+
+	t.Setenv("MCMA_BEARER_TOKEN_FILE", "")
+	authenticator, d = GetBearerTokenAuthenticator(map[string]interface{}{"auth_type": "JWT"})
+	if d != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", d)
+	}
+	testBearerTokenAuthenticate(t, authenticator, "env-token")
+}
+
+func TestBearerTokenAuthenticator_configuredTokenOverridesEnvVars(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MCMA_BEARER_TOKEN_FILE", tokenFile)
+	t.Setenv("MCMA_BEARER_TOKEN", "env-token")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"service_registry_url": "https://service-registry-example.mcma.io/api/",
+		"bearer_token_auth": []interface{}{
+			map[string]interface{}{"token": "inline-token"},
+		},
+	})
+	meta, di := configure(context.Background(), d)
+	if di.HasError() {
+		t.Fatalf("unexpected error configuring provider: %v", di)
+	}
+	testBearerTokenAuthenticate(t, meta.(*providerMeta).authenticators["JWT"], "inline-token")
+}
+
+func TestBearerTokenAuthenticator_noToken(t *testing.T) {
+	t.Setenv("MCMA_BEARER_TOKEN_FILE", "")
+	t.Setenv("MCMA_BEARER_TOKEN", "")
+
+	if _, d := GetBearerTokenAuthenticator(map[string]interface{}{}); !d.HasError() {
+		t.Fatal("expected error when no token is specified")
+	}
+}
@@ -0,0 +1,162 @@
+package mcma
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// knownDateModifiedSchema returns the schema of known_date_modified, which records the date_modified of a resource as
+// last written by the provider, or read while it matched state, for conflict detection. It is a computed attribute
+// as the plugin SDK does not let CRUD functions read or write the private state of a resource.
+func knownDateModifiedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The date and time at which the resource was last modified as known to Terraform, i.e. when it was last written by Terraform, or read without changes to the attributes managed by Terraform. When conflict_detection is enabled in the provider, updates fail if the resource was modified in the service registry since.",
+		Computed:    true,
+	}
+}
+
+// conflictIgnoredAttributes are the attributes that change whenever a resource is written, so are not compared to
+// tell whether it was modified remotely.
+var conflictIgnoredAttributes = map[string]bool{
+	"id":                  true,
+	"date_modified":       true,
+	"server_json":         true,
+	"known_date_modified": true,
+}
+
+// getChangedAttributes returns the attributes of r, besides conflictIgnoredAttributes, whose value in current is not
+// the one in prior, formatted for a diagnostic.
+func getChangedAttributes(r *schema.Resource, prior, current func(key string) interface{}) []string {
+	keys := make([]string, 0, len(r.Schema))
+	for k := range r.Schema {
+		if !conflictIgnoredAttributes[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []string
+	for _, k := range keys {
+		priorValue, currentValue := prior(k), current(k)
+		if attributeValuesEqual(priorValue, currentValue) {
+			continue
+		}
+		switch currentValue.(type) {
+		case string, bool, int, float64:
+			changes = append(changes, fmt.Sprintf("  - %s: %q => %q", k, fmt.Sprint(priorValue), fmt.Sprint(currentValue)))
+		default:
+			changes = append(changes, fmt.Sprintf("  - %s", k))
+		}
+	}
+	return changes
+}
+
+func getPriorValue(d *schema.ResourceData) func(key string) interface{} {
+	return func(key string) interface{} {
+		prior, _ := d.GetChange(key)
+		return prior
+	}
+}
+
+// setKnownDateModified records the date_modified of a resource that was just written by the provider.
+func setKnownDateModified(d *schema.ResourceData) {
+	_ = d.Set("known_date_modified", d.Get("date_modified"))
+}
+
+// refreshKnownDateModified is called after a resource was read from the service registry into d. It records the
+// date_modified that was read, unless attributes managed by Terraform were modified remotely since the prior state,
+// so that conflict detection still fails the next update and the remote changes are reviewed.
+func refreshKnownDateModified(d *schema.ResourceData, r *schema.Resource) {
+	if d.Get("known_date_modified").(string) == "" || len(getChangedAttributes(r, getPriorValue(d), d.Get)) == 0 {
+		setKnownDateModified(d)
+	}
+}
+
+// checkForConflict is called before a resource is updated when conflict_detection is enabled. It reads the resource
+// from the service registry with the given read function into a copy of the state, and fails if its date_modified is
+// not the one last known to the provider, listing the attributes that were changed remotely.
+func checkForConflict(d *schema.ResourceData, r *schema.Resource, description string, read func(remote *schema.ResourceData) diag.Diagnostics) diag.Diagnostics {
+	knownDateModified, _ := d.GetChange("known_date_modified")
+	if knownDateModified.(string) == "" {
+		// the state was not refreshed since known_date_modified was added
+		knownDateModified, _ = d.GetChange("date_modified")
+	}
+	if knownDateModified.(string) == "" {
+		return nil
+	}
+
+	// start from the prior state, so that attributes that affect how the resource is read, such as ignore_fields or
+	// the ordered parameter lists of a job profile, are the same as in state
+	keys := make([]string, 0, len(r.Schema))
+	for k := range r.Schema {
+		if k != "id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	remote := r.Data(nil)
+	remote.SetId(d.Id())
+	for _, k := range keys {
+		prior, _ := d.GetChange(k)
+		if err := remote.Set(k, prior); err != nil {
+			return diag.Errorf("error checking %s %s for conflicts: %s", description, d.Id(), err)
+		}
+	}
+
+	if di := read(remote); di.HasError() {
+		return di
+	}
+	if remote.Id() == "" {
+		return diag.Diagnostics{
+			diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Conflict updating %s %s.", description, d.Id()),
+				Detail:   "It was deleted from the MCMA service registry since it was last read. Run terraform plan again to review the change.",
+			},
+		}
+	}
+
+	remoteDateModified := remote.Get("date_modified").(string)
+	if remoteDateModified == knownDateModified.(string) {
+		return nil
+	}
+
+	changes := getChangedAttributes(r, getPriorValue(d), remote.Get)
+	if len(changes) == 0 {
+		if priorDateModified, _ := d.GetChange("date_modified"); priorDateModified.(string) == remoteDateModified {
+			changes = append(changes, "  (the changes were read when the state was refreshed, see the plan for the attributes they affect)")
+		} else {
+			changes = append(changes, "  (no changes to attributes managed by Terraform)")
+		}
+	}
+
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Conflict updating %s %s.", description, d.Id()),
+			Detail: fmt.Sprintf("It was modified in the MCMA service registry at %s, after it was last known to Terraform at version %s. "+
+				"The following attributes were changed remotely:\n%s\n\n"+
+				"Run terraform apply -refresh-only to accept the remote changes into state, then terraform plan to review them against the configuration.",
+				remoteDateModified, knownDateModified, strings.Join(changes, "\n")),
+			AttributePath: cty.GetAttrPath("date_modified"),
+		},
+	}
+}
+
+func attributeValuesEqual(a, b interface{}) bool {
+	if aSet, ok := a.(*schema.Set); ok {
+		if bSet, ok := b.(*schema.Set); ok {
+			return aSet.Equal(bSet)
+		}
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,129 @@
+package mcma
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+)
+
+func TestCheckForConflict(t *testing.T) {
+	service := mcmamodel.Service{
+		Id:           "https://service-registry.mcma.io/api/services/1",
+		DateCreated:  time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC),
+		DateModified: time.Date(2022, 3, 2, 11, 30, 0, 0, time.UTC),
+		Name:         "MediaInfo AME Service",
+		AuthType:     "AWS4",
+		JobType:      "AmeJob",
+	}
+
+	r := resourceService()
+	stateData := schema.TestResourceDataRaw(t, r.Schema, nil)
+	if di := setServiceResourceData(stateData, service); di.HasError() {
+		t.Fatalf("error setting state: %v", di)
+	}
+	setKnownDateModified(stateData)
+	d := r.Data(stateData.State())
+
+	readAs := func(remoteService *mcmamodel.Service) func(remote *schema.ResourceData) diag.Diagnostics {
+		return func(remote *schema.ResourceData) diag.Diagnostics {
+			if remoteService == nil {
+				remote.SetId("")
+				return nil
+			}
+			return setServiceResourceData(remote, *remoteService)
+		}
+	}
+
+	if di := checkForConflict(d, r, "service", readAs(&service)); di != nil {
+		t.Errorf("expected no conflict when unmodified, got %v", di)
+	}
+
+	modified := service
+	modified.DateModified = time.Date(2022, 3, 3, 9, 0, 0, 0, time.UTC)
+	modified.Name = "MediaInfo Service"
+	di := checkForConflict(d, r, "service", readAs(&modified))
+	if !di.HasError() {
+		t.Fatal("expected conflict when modified remotely")
+	}
+	for _, expected := range []string{"2022-03-03T09:00:00Z", "2022-03-02T11:30:00Z", `name: "MediaInfo AME Service" => "MediaInfo Service"`} {
+		if !strings.Contains(di[0].Detail, expected) {
+			t.Errorf("expected %q in conflict detail, got %q", expected, di[0].Detail)
+		}
+	}
+	if strings.Contains(di[0].Detail, "auth_type") {
+		t.Errorf("expected only changed attributes in conflict detail, got %q", di[0].Detail)
+	}
+
+	if di := checkForConflict(d, r, "service", readAs(nil)); !di.HasError() || !strings.Contains(di[0].Detail, "deleted") {
+		t.Errorf("expected conflict when deleted remotely, got %v", di)
+	}
+}
+
+func TestCheckForConflict_modifiedBeforeRefresh(t *testing.T) {
+	service := mcmamodel.Service{
+		Id:           "https://service-registry.mcma.io/api/services/1",
+		DateCreated:  time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC),
+		DateModified: time.Date(2022, 3, 2, 11, 30, 0, 0, time.UTC),
+		Name:         "MediaInfo AME Service",
+		AuthType:     "AWS4",
+		JobType:      "AmeJob",
+	}
+	modified := service
+	modified.DateModified = time.Date(2022, 3, 3, 9, 0, 0, 0, time.UTC)
+	modified.Name = "MediaInfo Service"
+
+	r := resourceService()
+	written := schema.TestResourceDataRaw(t, r.Schema, nil)
+	if di := setServiceResourceData(written, service); di.HasError() {
+		t.Fatalf("error setting state: %v", di)
+	}
+	setKnownDateModified(written)
+
+	refresh := func(state *schema.ResourceData, remoteService mcmamodel.Service) *schema.ResourceData {
+		d := r.Data(state.State())
+		if di := setServiceResourceData(d, remoteService); di.HasError() {
+			t.Fatalf("error refreshing state: %v", di)
+		}
+		refreshKnownDateModified(d, r)
+		return r.Data(d.State())
+	}
+	readAs := func(remoteService mcmamodel.Service) func(remote *schema.ResourceData) diag.Diagnostics {
+		return func(remote *schema.ResourceData) diag.Diagnostics {
+			return setServiceResourceData(remote, remoteService)
+		}
+	}
+
+	// the service is modified remotely before the refresh, which reads the changes but keeps the date last known
+	refreshed := refresh(written, modified)
+	if known := refreshed.Get("known_date_modified").(string); known != "2022-03-02T11:30:00Z" {
+		t.Fatalf("expected refresh to keep the known date_modified, got %s", known)
+	}
+	di := checkForConflict(refreshed, r, "service", readAs(modified))
+	if !di.HasError() {
+		t.Fatal("expected conflict when modified remotely before the refresh")
+	}
+	for _, expected := range []string{"2022-03-03T09:00:00Z", "2022-03-02T11:30:00Z", "read when the state was refreshed"} {
+		if !strings.Contains(di[0].Detail, expected) {
+			t.Errorf("expected %q in conflict detail, got %q", expected, di[0].Detail)
+		}
+	}
+
+	// once the remote changes are in the prior state, the next refresh accepts them
+	accepted := refresh(refreshed, modified)
+	if di := checkForConflict(accepted, r, "service", readAs(modified)); di != nil {
+		t.Errorf("expected no conflict once the remote changes were refreshed into state, got %v", di)
+	}
+
+	// a remote modification that does not change attributes managed by Terraform is accepted straight away
+	touched := service
+	touched.DateModified = time.Date(2022, 3, 4, 8, 0, 0, 0, time.UTC)
+	refreshed = refresh(written, touched)
+	if di := checkForConflict(refreshed, r, "service", readAs(touched)); di != nil {
+		t.Errorf("expected no conflict when only date_modified changed remotely, got %v", di)
+	}
+}
@@ -0,0 +1,111 @@
+package mcma
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+)
+
+// The service registry and MCMA services are eventually consistent, so a resource that was just created, updated or
+// deleted may not be returned as such straight away. The CRUD functions poll until it is, for at most the timeout of
+// the operation, which the SDK applies to the context they are called with.
+const (
+	defaultCreateTimeout = 5 * time.Minute
+	defaultReadTimeout   = 2 * time.Minute
+	defaultUpdateTimeout = 5 * time.Minute
+	defaultDeleteTimeout = 5 * time.Minute
+)
+
+var (
+	consistencyMinPollInterval = 500 * time.Millisecond
+	consistencyMaxPollInterval = 5 * time.Second
+)
+
+func defaultResourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultCreateTimeout),
+		Read:   schema.DefaultTimeout(defaultReadTimeout),
+		Update: schema.DefaultTimeout(defaultUpdateTimeout),
+		Delete: schema.DefaultTimeout(defaultDeleteTimeout),
+	}
+}
+
+// versionGetter gets a resource from the service registry, or nil if it does not exist, along with the date at which
+// it was last modified.
+type versionGetter func() (interface{}, time.Time, error)
+
+// waitFor calls check until it reports that it is done, doubling the time between calls, until the context is done.
+func waitFor(ctx context.Context, description string, check func() (bool, error)) error {
+	interval := consistencyMinPollInterval
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Waiting %s for %s", interval, description))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for %s: %s", description, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > consistencyMaxPollInterval {
+			interval = consistencyMaxPollInterval
+		}
+	}
+}
+
+// readAfterWrite polls get until it returns a resource that was modified no earlier than written, i.e. the version
+// that was just written to the service registry or a later one. A zero written time only waits for the resource to
+// exist.
+func readAfterWrite(ctx context.Context, description string, written time.Time, get versionGetter) (interface{}, error) {
+	var resource interface{}
+	err := waitFor(ctx, description+" to be readable", func() (bool, error) {
+		var dateModified time.Time
+		var err error
+		resource, dateModified, err = get()
+		if err != nil {
+			return false, err
+		}
+		return resource != nil && !dateModified.Before(written), nil
+	})
+	return resource, err
+}
+
+// waitUntilDeleted polls get until the resource is no longer returned.
+func waitUntilDeleted(ctx context.Context, description string, get versionGetter) error {
+	return waitFor(ctx, description+" to be deleted", func() (bool, error) {
+		resource, _, err := get()
+		return resource == nil, err
+	})
+}
+
+// getMcmaResourceVersion returns a versionGetter for an untyped MCMA resource.
+func getMcmaResourceVersion(ctx context.Context, resourceManager *mcmaclient.ResourceManager, resourceType string, resourceId string) versionGetter {
+	return func() (interface{}, time.Time, error) {
+		resource, err := resourceManager.GetResource(ctx, resourceType, resourceId)
+		if err != nil || resource == nil {
+			return nil, time.Time{}, err
+		}
+		return resource, getMcmaResourceDateModified(resource), nil
+	}
+}
+
+func getMcmaResourceDateModified(resource interface{}) time.Time {
+	if resourceMap, ok := resource.(map[string]interface{}); ok {
+		if dateModified, ok := resourceMap["dateModified"].(string); ok {
+			return parseDate(dateModified)
+		}
+	}
+	return time.Time{}
+}
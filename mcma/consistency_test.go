@@ -0,0 +1,83 @@
+package mcma
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setFastConsistencyPolling(t *testing.T) {
+	minPollInterval, maxPollInterval := consistencyMinPollInterval, consistencyMaxPollInterval
+	consistencyMinPollInterval, consistencyMaxPollInterval = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() {
+		consistencyMinPollInterval, consistencyMaxPollInterval = minPollInterval, maxPollInterval
+	})
+}
+
+func TestReadAfterWrite(t *testing.T) {
+	setFastConsistencyPolling(t)
+
+	written := time.Date(2022, 3, 2, 11, 30, 0, 0, time.UTC)
+	versions := []time.Time{{}, written.Add(-time.Hour), written}
+	calls := 0
+	get := func() (interface{}, time.Time, error) {
+		version := versions[calls]
+		calls++
+		if version.IsZero() {
+			return nil, time.Time{}, nil
+		}
+		return version.Format(time.RFC3339), version, nil
+	}
+
+	resource, err := readAfterWrite(context.Background(), "resource", written, get)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 || resource != "2022-03-02T11:30:00Z" {
+		t.Errorf("expected the written version after 3 calls, got %v after %d calls", resource, calls)
+	}
+}
+
+func TestReadAfterWriteTimeout(t *testing.T) {
+	setFastConsistencyPolling(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := readAfterWrite(ctx, "resource", time.Time{}, func() (interface{}, time.Time, error) {
+		return nil, time.Time{}, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "timeout waiting for resource to be readable") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestWaitUntilDeleted(t *testing.T) {
+	setFastConsistencyPolling(t)
+
+	calls := 0
+	err := waitUntilDeleted(context.Background(), "resource", func() (interface{}, time.Time, error) {
+		calls++
+		if calls < 3 {
+			return "resource", time.Time{}, nil
+		}
+		return nil, time.Time{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestGetMcmaResourceDateModified(t *testing.T) {
+	resource := map[string]interface{}{"dateModified": "2022-03-02T11:30:00.123Z"}
+	if expected := time.Date(2022, 3, 2, 11, 30, 0, 123000000, time.UTC); !getMcmaResourceDateModified(resource).Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, getMcmaResourceDateModified(resource))
+	}
+	if !getMcmaResourceDateModified(map[string]interface{}{}).IsZero() {
+		t.Error("expected zero time without dateModified")
+	}
+}
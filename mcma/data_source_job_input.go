@@ -0,0 +1,174 @@
+package mcma
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceJobInput() *schema.Resource {
+	return &schema.Resource{
+		Description: "Validates the input of a job against the input parameters of its job profile, reporting missing required parameters, unknown parameters and values that do not have the declared type or are not one of the allowed values.",
+
+		ReadContext: dataSourceJobInputRead,
+
+		Schema: map[string]*schema.Schema{
+			"job_profile_id": {
+				Type:         schema.TypeString,
+				Description:  "The ID of the job profile to validate against. Exactly one of job_profile_id or job_profile_name must be specified.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"job_profile_id", "job_profile_name"},
+			},
+			"job_profile_name": {
+				Type:         schema.TypeString,
+				Description:  "The name of the job profile to validate against. Exactly one of job_profile_id or job_profile_name must be specified.",
+				Optional:     true,
+				ExactlyOneOf: []string{"job_profile_id", "job_profile_name"},
+			},
+			"job_input": {
+				Type:         schema.TypeString,
+				Description:  "The JSON object with the input parameters of the job.",
+				Required:     true,
+				ValidateFunc: validateJsonObject,
+			},
+			"allow_unknown_parameters": {
+				Type:        schema.TypeBool,
+				Description: "Flag indicating if parameters that the job profile does not declare are allowed. They are listed in unknown_parameters either way.",
+				Optional:    true,
+				Default:     false,
+			},
+			"fail_on_error": {
+				Type:        schema.TypeBool,
+				Description: "Flag indicating if the data source should fail when the job input is not valid. Set to false to only report the errors in its attributes.",
+				Optional:    true,
+				Default:     true,
+			},
+			"valid": {
+				Type:        schema.TypeBool,
+				Description: "Whether the job input is valid for the job profile.",
+				Computed:    true,
+			},
+			"missing_parameters": {
+				Type:        schema.TypeList,
+				Description: "The names of the required input parameters that are missing from the job input.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"unknown_parameters": {
+				Type:        schema.TypeList,
+				Description: "The names of the parameters in the job input that the job profile does not declare.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"type_mismatch": {
+				Type:        schema.TypeList,
+				Description: "The parameters in the job input whose value does not have the type declared in the job profile.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the parameter.",
+							Computed:    true,
+						},
+						"expected_type": {
+							Type:        schema.TypeString,
+							Description: "The type of the parameter declared in the job profile.",
+							Computed:    true,
+						},
+						"actual_type": {
+							Type:        schema.TypeString,
+							Description: "The type of the value in the job input, i.e. a JSON type or the @type of an object.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"errors": {
+				Type:        schema.TypeList,
+				Description: "Descriptions of all problems found in the job input.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"normalized_json": {
+				Type:        schema.TypeString,
+				Description: "The JSON of the job input with sorted keys and without insignificant whitespace. Numbers are kept as they were written.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceJobInputRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	jobProfileId := d.Get("job_profile_id").(string)
+	if name := d.Get("job_profile_name").(string); name != "" {
+		found, di := getJobProfileByName(ctx, resourceManager, name)
+		if di != nil {
+			return di
+		}
+		if found == nil {
+			return diag.Errorf("job profile with name %s not found", name)
+		}
+		jobProfileId = found.Id
+	}
+
+	jobProfile, err := getJobProfileDocument(ctx, resourceManager, jobProfileId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if jobProfile == nil {
+		return diag.Errorf("job profile with id %s not found", jobProfileId)
+	}
+
+	jobInput, err := decodeJobInput(d.Get("job_input").(string))
+	if err != nil {
+		return diag.Errorf("error parsing job_input: %s", err)
+	}
+
+	validation := validateJobInput(*jobProfile, jobInput, d.Get("allow_unknown_parameters").(bool))
+
+	normalizedJson, err := normalizeJobInput(jobInput)
+	if err != nil {
+		return diag.Errorf("error encoding job_input: %s", err)
+	}
+
+	var typeMismatches []map[string]interface{}
+	for _, mismatch := range validation.TypeMismatches {
+		typeMismatches = append(typeMismatches, map[string]interface{}{
+			"name":          mismatch.Name,
+			"expected_type": mismatch.ExpectedType,
+			"actual_type":   mismatch.ActualType,
+		})
+	}
+
+	d.SetId(jobProfileId)
+	_ = d.Set("job_profile_id", jobProfileId)
+	_ = d.Set("valid", validation.valid())
+	_ = d.Set("missing_parameters", validation.MissingParameters)
+	_ = d.Set("unknown_parameters", validation.UnknownParameters)
+	if err = d.Set("type_mismatch", typeMismatches); err != nil {
+		return diag.Errorf("error setting type_mismatch for job input: %s", err)
+	}
+	_ = d.Set("errors", validation.Errors)
+	_ = d.Set("normalized_json", normalizedJson)
+
+	if !validation.valid() && d.Get("fail_on_error").(bool) {
+		return diag.Errorf("job input is not valid for job profile %s:\n  - %s", jobProfile.Name, strings.Join(validation.Errors, "\n  - "))
+	}
+
+	return diag.Diagnostics{}
+}
@@ -0,0 +1,90 @@
+package mcma
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMcmaJobInputDataSource_basic(t *testing.T) {
+	profileName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaJobProfileDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaJobInputDataSource(profileName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("data.mcma_job_input.valid", "valid", "true"),
+						resource.TestCheckResourceAttr("data.mcma_job_input.valid", "normalized_json", `{"param1":"value","param2":2}`),
+						resource.TestCheckResourceAttr("data.mcma_job_input.invalid", "valid", "false"),
+						resource.TestCheckResourceAttr("data.mcma_job_input.invalid", "missing_parameters.0", "param1"),
+						resource.TestCheckResourceAttr("data.mcma_job_input.invalid", "unknown_parameters.0", "param3"),
+						resource.TestCheckResourceAttr("data.mcma_job_input.invalid", "type_mismatch.0.name", "param2"),
+						resource.TestCheckResourceAttr("data.mcma_job_input.invalid", "type_mismatch.0.actual_type", "string"),
+					),
+				},
+				{
+					Config:      testAccountMcmaJobInputDataSourceFailing(profileName, providerConfig),
+					ExpectError: regexp.MustCompile("missing required input parameter param1"),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+}
+
+func testAccountMcmaJobInputDataSourceProfile(profileName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job_profile" "job_profile_%s" {
+  name = "%s"
+  input_parameter {
+	name = "param1"
+	type = "string"
+  }
+  input_parameter {
+	name = "param2"
+	type = "number"
+	optional = true
+  }
+}
+`, providerConfig, profileName, profileName)
+}
+
+func testAccountMcmaJobInputDataSource(profileName string, providerConfig string) string {
+	return testAccountMcmaJobInputDataSourceProfile(profileName, providerConfig) + fmt.Sprintf(`
+data "mcma_job_input" "valid" {
+  job_profile_id = mcma_job_profile.job_profile_%s.id
+  job_input = jsonencode({
+	param2 = 2
+	param1 = "value"
+  })
+}
+
+data "mcma_job_input" "invalid" {
+  job_profile_name = mcma_job_profile.job_profile_%s.name
+  fail_on_error    = false
+  job_input = jsonencode({
+	param2 = "2"
+	param3 = true
+  })
+}
+`, profileName, profileName)
+}
+
+func testAccountMcmaJobInputDataSourceFailing(profileName string, providerConfig string) string {
+	return testAccountMcmaJobInputDataSourceProfile(profileName, providerConfig) + fmt.Sprintf(`
+data "mcma_job_input" "invalid" {
+  job_profile_id = mcma_job_profile.job_profile_%s.id
+  job_input      = jsonencode({})
+}
+`, profileName)
+}
@@ -0,0 +1,221 @@
+package mcma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// jobProfileDataSourceSchema returns the computed attributes of a job profile as exposed by the job profile data
+// sources.
+func jobProfileDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Description: "The ID of the job profile. MCMA IDs are always absolute urls.",
+			Computed:    true,
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of the job profile.",
+			Computed:    true,
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Description: "The MCMA type of resource. This value will always be 'JobProfile'.",
+			Computed:    true,
+		},
+		"date_created": {
+			Type:        schema.TypeString,
+			Description: "The date and time at which the job profile data was created.",
+			Computed:    true,
+		},
+		"date_modified": {
+			Type:        schema.TypeString,
+			Description: "The date and time at which the job profile data was last modified.",
+			Computed:    true,
+		},
+		"input_parameter": {
+			Type:        schema.TypeList,
+			Description: "The input parameters of the job profile, including optional ones.",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Description: "The name of the input parameter.",
+						Computed:    true,
+					},
+					"type": {
+						Type:        schema.TypeString,
+						Description: "The type of the input parameter.",
+						Computed:    true,
+					},
+					"optional": {
+						Type:        schema.TypeBool,
+						Description: "Flag indicating if this input parameter must be provided or not",
+						Computed:    true,
+					},
+					"description": {
+						Type:        schema.TypeString,
+						Description: "A description of the input parameter.",
+						Computed:    true,
+					},
+					"default_value": {
+						Type:        schema.TypeString,
+						Description: "The value used for the input parameter when it is not provided.",
+						Computed:    true,
+					},
+					"allowed_values": {
+						Type:        schema.TypeList,
+						Description: "The values that are allowed for the input parameter.",
+						Computed:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+		"output_parameter": {
+			Type:        schema.TypeList,
+			Description: "The output parameters of the job profile.",
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Description: "The name of the output parameter.",
+						Computed:    true,
+					},
+					"type": {
+						Type:        schema.TypeString,
+						Description: "The type of the output parameter.",
+						Computed:    true,
+					},
+					"description": {
+						Type:        schema.TypeString,
+						Description: "A description of the output parameter.",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"custom_properties": {
+			Type:        schema.TypeMap,
+			Description: "Additional properties of the job profile. Values that are not strings are JSON encoded.",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"custom_properties_json": {
+			Type:        schema.TypeString,
+			Description: "Additional properties of the job profile as a JSON object.",
+			Computed:    true,
+		},
+	}
+}
+
+func dataSourceJobProfile() *schema.Resource {
+	s := jobProfileDataSourceSchema()
+	s["id"].Description = "The ID of the job profile. MCMA IDs are always absolute urls. Exactly one of id or name must be specified."
+	s["id"].Optional = true
+	s["id"].ExactlyOneOf = []string{"id", "name"}
+	s["name"].Description = "The name of the job profile. It is an error if more than one job profile has this name. Exactly one of id or name must be specified."
+	s["name"].Optional = true
+	s["name"].ExactlyOneOf = []string{"id", "name"}
+
+	return &schema.Resource{
+		Description: "Looks up job profile data registered in an MCMA Service Registry by ID or by name",
+
+		ReadContext: dataSourceJobProfileRead,
+
+		Schema: s,
+	}
+}
+
+// flattenCustomProperties converts the custom properties of an MCMA resource to a map of strings, JSON encoding any
+// values that are not strings already.
+func flattenCustomProperties(custom map[string]interface{}) map[string]interface{} {
+	flattened := make(map[string]interface{})
+	for key, value := range custom {
+		if s, ok := value.(string); ok {
+			flattened[key] = s
+		} else if b, err := json.Marshal(value); err == nil {
+			flattened[key] = string(b)
+		} else {
+			flattened[key] = fmt.Sprint(value)
+		}
+	}
+	return flattened
+}
+
+func flattenJobProfile(jobProfile jobProfileDocument) (map[string]interface{}, error) {
+	custom := jobProfile.Custom
+	if custom == nil {
+		custom = make(map[string]interface{})
+	}
+	customJson, err := json.Marshal(custom)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":                     jobProfile.Id,
+		"name":                   jobProfile.Name,
+		"type":                   jobProfile.Type,
+		"date_created":           formatDate(dateValue(jobProfile.DateCreated)),
+		"date_modified":          formatDate(dateValue(jobProfile.DateModified)),
+		"input_parameter":        flattenJobProfileInputParameters(jobProfile),
+		"output_parameter":       flattenJobProfileOutputParameters(jobProfile),
+		"custom_properties":      flattenCustomProperties(jobProfile.Custom),
+		"custom_properties_json": string(customJson),
+	}, nil
+}
+
+func dataSourceJobProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	jobProfileId := d.Get("id").(string)
+	if jobProfileId == "" {
+		name := d.Get("name").(string)
+		found, di := getJobProfileByName(ctx, resourceManager, name)
+		if di != nil {
+			return di
+		}
+		if found == nil {
+			return diag.Errorf("job profile with name %s not found", name)
+		}
+		jobProfileId = found.Id
+	}
+
+	jobProfile, err := getJobProfileDocument(ctx, resourceManager, jobProfileId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if jobProfile == nil {
+		return diag.Errorf("job profile with id %s not found", jobProfileId)
+	}
+
+	flattened, err := flattenJobProfile(*jobProfile)
+	if err != nil {
+		return diag.Errorf("error encoding custom properties for job profile with id %s: %s", jobProfile.Id, err)
+	}
+	for key, value := range flattened {
+		if key == "id" {
+			continue
+		}
+		if err := d.Set(key, value); err != nil {
+			return diag.Errorf("error setting %s for job profile with id %s: %s", key, jobProfile.Id, err)
+		}
+	}
+
+	return diag.Diagnostics{}
+}
@@ -0,0 +1,74 @@
+package mcma
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMcmaJobProfileDataSource_basic(t *testing.T) {
+	profileName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaJobProfileDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaJobProfileDataSource(profileName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrPair("data.mcma_job_profile.by_name", "id", "mcma_job_profile.job_profile_"+profileName, "id"),
+						resource.TestCheckResourceAttrPair("data.mcma_job_profile.by_id", "name", "mcma_job_profile.job_profile_"+profileName, "name"),
+						resource.TestCheckResourceAttr("data.mcma_job_profile.by_name", "input_parameter.#", "2"),
+						resource.TestCheckTypeSetElemNestedAttrs("data.mcma_job_profile.by_name", "input_parameter.*", map[string]string{
+							"name":     "param2",
+							"type":     "number",
+							"optional": "true",
+						}),
+						resource.TestCheckResourceAttr("data.mcma_job_profile.by_id", "output_parameter.#", "1"),
+						resource.TestCheckResourceAttr("data.mcma_job_profile.by_id", "custom_properties.customprop1", "customprop1val"),
+					),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+	resource.Test(t, createTestCase(getMcmaApiKeyProviderConfigFromEnvVars()))
+}
+
+func testAccountMcmaJobProfileDataSource(profileName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job_profile" "job_profile_%s" {
+  name = "%s"
+  input_parameter {
+	name = "param1"
+	type = "string"
+  }
+  input_parameter {
+	name = "param2"
+	type = "number"
+	optional = true
+  }
+  output_parameter {
+	name = "outparam1"
+	type = "string"
+  }
+  custom_properties = {
+	customprop1 = "customprop1val"
+  }
+}
+
+data "mcma_job_profile" "by_name" {
+  name = mcma_job_profile.job_profile_%s.name
+}
+
+data "mcma_job_profile" "by_id" {
+  id = mcma_job_profile.job_profile_%s.id
+}
+`, providerConfig, profileName, profileName, profileName, profileName)
+}
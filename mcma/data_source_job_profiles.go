@@ -0,0 +1,171 @@
+package mcma
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceJobProfiles() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists job profiles registered in an MCMA Service Registry, optionally filtered",
+
+		ReadContext: dataSourceJobProfilesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_prefix": {
+				Type:        schema.TypeString,
+				Description: "A prefix that the name of a job profile must start with.",
+				Optional:    true,
+			},
+			"input_parameter_name": {
+				Type:        schema.TypeString,
+				Description: "The name of an input parameter, required or optional, that a job profile must have.",
+				Optional:    true,
+			},
+			"input_parameter_type": {
+				Type:        schema.TypeString,
+				Description: "The type of an input parameter, required or optional, that a job profile must have. When combined with input_parameter_name, both must match the same parameter.",
+				Optional:    true,
+			},
+			"output_parameter_name": {
+				Type:        schema.TypeString,
+				Description: "The name of an output parameter that a job profile must have.",
+				Optional:    true,
+			},
+			"output_parameter_type": {
+				Type:        schema.TypeString,
+				Description: "The type of an output parameter that a job profile must have. When combined with output_parameter_name, both must match the same parameter.",
+				Optional:    true,
+			},
+			"custom_properties": {
+				Type:        schema.TypeMap,
+				Description: "Custom properties that a job profile must have. Values that are not strings are compared with their JSON encoding.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Description: "The IDs of the matching job profiles.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"job_profiles": {
+				Type:        schema.TypeList,
+				Description: "The matching job profiles.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: jobProfileDataSourceSchema(),
+				},
+			},
+		},
+	}
+}
+
+func hasJobParameter(parameters []jobParameterDocument, name, parameterType string) bool {
+	for _, parameter := range parameters {
+		if (name == "" || parameter.ParameterName == name) && (parameterType == "" || parameter.ParameterType == parameterType) {
+			return true
+		}
+	}
+	return false
+}
+
+func jobProfileMatches(jobProfile jobProfileDocument, d *schema.ResourceData) bool {
+	if !strings.HasPrefix(jobProfile.Name, d.Get("name_prefix").(string)) {
+		return false
+	}
+
+	inputName := d.Get("input_parameter_name").(string)
+	inputType := d.Get("input_parameter_type").(string)
+	if inputName != "" || inputType != "" {
+		if !hasJobParameter(jobProfile.InputParameters, inputName, inputType) &&
+			!hasJobParameter(jobProfile.OptionalInputParameters, inputName, inputType) {
+			return false
+		}
+	}
+
+	outputName := d.Get("output_parameter_name").(string)
+	outputType := d.Get("output_parameter_type").(string)
+	if outputName != "" || outputType != "" {
+		if !hasJobParameter(jobProfile.OutputParameters, outputName, outputType) {
+			return false
+		}
+	}
+
+	custom := flattenCustomProperties(jobProfile.Custom)
+	for key, value := range d.Get("custom_properties").(map[string]interface{}) {
+		if actual, found := custom[key]; !found || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func dataSourceJobProfilesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	// the untyped functions are used so that the documentation of the parameters is read as well
+	results, err := resourceManager.QueryResources(ctx, "JobProfile", map[string]string{})
+	if err != nil {
+		return diag.Errorf("error querying job profiles: %s", err)
+	}
+
+	ids := make([]interface{}, 0)
+	jobProfiles := make([]interface{}, 0)
+	for _, result := range results {
+		jobProfile, err := jobProfileDocumentFromMap(result)
+		if err != nil {
+			return diag.Errorf("error parsing job profile with id %v: %s", result["id"], err)
+		}
+		if !jobProfileMatches(jobProfile, d) {
+			continue
+		}
+		ids = append(ids, jobProfile.Id)
+		flattened, err := flattenJobProfile(jobProfile)
+		if err != nil {
+			return diag.Errorf("error encoding custom properties for job profile with id %s: %s", jobProfile.Id, err)
+		}
+		jobProfiles = append(jobProfiles, flattened)
+	}
+
+	filters := []string{
+		"job_profiles",
+		d.Get("name_prefix").(string),
+		d.Get("input_parameter_name").(string),
+		d.Get("input_parameter_type").(string),
+		d.Get("output_parameter_name").(string),
+		d.Get("output_parameter_type").(string),
+	}
+	customProperties := d.Get("custom_properties").(map[string]interface{})
+	var keys []string
+	for key := range customProperties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		filters = append(filters, fmt.Sprintf("%s=%s", key, customProperties[key]))
+	}
+
+	d.SetId(strings.Join(filters, "|"))
+	if err := d.Set("ids", ids); err != nil {
+		return diag.Errorf("error setting ids: %s", err)
+	}
+	if err := d.Set("job_profiles", jobProfiles); err != nil {
+		return diag.Errorf("error setting job_profiles: %s", err)
+	}
+
+	return diag.Diagnostics{}
+}
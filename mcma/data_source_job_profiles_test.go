@@ -0,0 +1,106 @@
+package mcma
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMcmaJobProfilesDataSource_filtered(t *testing.T) {
+	profileName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaJobProfileDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaJobProfilesDataSource(profileName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("data.mcma_job_profiles.by_prefix", "job_profiles.#", "2"),
+						resource.TestCheckResourceAttr("data.mcma_job_profiles.by_input", "ids.#", "1"),
+						resource.TestCheckResourceAttrPair("data.mcma_job_profiles.by_input", "ids.0", "mcma_job_profile.job_profile_"+profileName+"_1", "id"),
+						resource.TestCheckResourceAttr("data.mcma_job_profiles.by_output", "ids.#", "1"),
+						resource.TestCheckResourceAttrPair("data.mcma_job_profiles.by_output", "ids.0", "mcma_job_profile.job_profile_"+profileName+"_2", "id"),
+						resource.TestCheckResourceAttr("data.mcma_job_profiles.by_custom_property", "job_profiles.#", "1"),
+						resource.TestCheckResourceAttrPair("data.mcma_job_profiles.by_custom_property", "job_profiles.0.name", "mcma_job_profile.job_profile_"+profileName+"_2", "name"),
+					),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+	resource.Test(t, createTestCase(getMcmaApiKeyProviderConfigFromEnvVars()))
+}
+
+func testAccountMcmaJobProfilesDataSource(profileName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job_profile" "job_profile_%s_1" {
+  name = "%s_1"
+  input_parameter {
+	name = "inputFile"
+	type = "Locator"
+	optional = true
+  }
+  output_parameter {
+	name = "outputFile"
+	type = "Locator"
+  }
+  custom_properties = {
+	tier = "standard"
+  }
+}
+
+resource "mcma_job_profile" "job_profile_%s_2" {
+  name = "%s_2"
+  input_parameter {
+	name = "inputText"
+	type = "string"
+  }
+  output_parameter {
+	name = "outputText"
+	type = "string"
+  }
+  custom_properties = {
+	tier = "premium"
+  }
+}
+
+data "mcma_job_profiles" "by_prefix" {
+  name_prefix = "%s_"
+  depends_on = [mcma_job_profile.job_profile_%s_1, mcma_job_profile.job_profile_%s_2]
+}
+
+data "mcma_job_profiles" "by_input" {
+  name_prefix = "%s_"
+  input_parameter_name = "inputFile"
+  input_parameter_type = "Locator"
+  depends_on = [mcma_job_profile.job_profile_%s_1, mcma_job_profile.job_profile_%s_2]
+}
+
+data "mcma_job_profiles" "by_output" {
+  name_prefix = "%s_"
+  output_parameter_type = "string"
+  depends_on = [mcma_job_profile.job_profile_%s_1, mcma_job_profile.job_profile_%s_2]
+}
+
+data "mcma_job_profiles" "by_custom_property" {
+  name_prefix = "%s_"
+  custom_properties = {
+	tier = "premium"
+  }
+  depends_on = [mcma_job_profile.job_profile_%s_1, mcma_job_profile.job_profile_%s_2]
+}
+`, providerConfig,
+		profileName, profileName,
+		profileName, profileName,
+		profileName, profileName, profileName,
+		profileName, profileName, profileName,
+		profileName, profileName, profileName,
+		profileName, profileName, profileName)
+}
@@ -0,0 +1,115 @@
+package mcma
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// mcmaResourceDataSourceSchema returns the computed attributes of an arbitrary MCMA resource as exposed by the
+// mcma_resource and mcma_resources data sources.
+func mcmaResourceDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:        schema.TypeString,
+			Description: "The MCMA type of resource.",
+			Computed:    true,
+		},
+		"id": {
+			Type:        schema.TypeString,
+			Description: "The ID of the resource. MCMA IDs are always absolute urls.",
+			Computed:    true,
+		},
+		"resource_json": {
+			Type:        schema.TypeString,
+			Description: "The JSON of the resource as returned by the service, including its id, type and dates.",
+			Computed:    true,
+		},
+		"attributes": {
+			Type:        schema.TypeMap,
+			Description: "The top-level string, number and boolean properties of the resource, converted to strings. Use jsondecode on resource_json to access nested properties.",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+	}
+}
+
+func dataSourceMcmaResource() *schema.Resource {
+	s := mcmaResourceDataSourceSchema()
+	s["type"].Computed = false
+	s["type"].Required = true
+	s["id"].Computed = false
+	s["id"].Required = true
+
+	return &schema.Resource{
+		Description: "Looks up an arbitrary MCMA resource by type and ID through a service registered in the MCMA service registry",
+
+		ReadContext: dataSourceMcmaResourceRead,
+
+		Schema: s,
+	}
+}
+
+// flattenMcmaResourceAttributes returns the top-level scalar properties of an MCMA resource as strings. Objects,
+// arrays and nulls are skipped.
+func flattenMcmaResourceAttributes(resource map[string]interface{}) map[string]interface{} {
+	attributes := make(map[string]interface{})
+	for key, value := range resource {
+		switch value.(type) {
+		case string, float64, bool:
+			attributes[key], _ = jsonValueToString(value)
+		}
+	}
+	return attributes
+}
+
+func flattenMcmaResource(resource map[string]interface{}) (map[string]interface{}, error) {
+	jsonBytes, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceType, _ := resource["@type"].(string)
+	resourceId, _ := resource["id"].(string)
+
+	return map[string]interface{}{
+		"type":          resourceType,
+		"id":            resourceId,
+		"resource_json": string(jsonBytes),
+		"attributes":    flattenMcmaResourceAttributes(resource),
+	}, nil
+}
+
+func dataSourceMcmaResourceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	resourceType := d.Get("type").(string)
+	resourceId := d.Get("id").(string)
+	resource, err := resourceManager.GetResource(ctx, resourceType, resourceId)
+	if err != nil {
+		return diag.Errorf("error getting resource of type %s with id %s: %s", resourceType, resourceId, err)
+	}
+	if resource == nil {
+		return diag.Errorf("resource of type %s with id %s not found", resourceType, resourceId)
+	}
+
+	flattened, err := flattenMcmaResource(resource)
+	if err != nil {
+		return diag.Errorf("error parsing json for resource of type %s with id %s: %s", resourceType, resourceId, err)
+	}
+
+	d.SetId(resourceId)
+	_ = d.Set("resource_json", flattened["resource_json"])
+	if err := d.Set("attributes", flattened["attributes"]); err != nil {
+		return diag.Errorf("error setting attributes for resource of type %s with id %s: %s", resourceType, resourceId, err)
+	}
+
+	return diag.Diagnostics{}
+}
@@ -0,0 +1,80 @@
+package mcma
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestFlattenMcmaResourceAttributes(t *testing.T) {
+	attributes := flattenMcmaResourceAttributes(map[string]interface{}{
+		"@type":    "BMEssence",
+		"id":       "https://service.mcma.io/api/bm-essences/1",
+		"duration": float64(12.5),
+		"frames":   float64(300),
+		"archived": false,
+		"metadata": map[string]interface{}{"name": "nested"},
+		"locators": []interface{}{"s3://bucket/key"},
+		"title":    nil,
+	})
+
+	expected := map[string]interface{}{
+		"@type":    "BMEssence",
+		"id":       "https://service.mcma.io/api/bm-essences/1",
+		"duration": "12.5",
+		"frames":   "300",
+		"archived": "false",
+	}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Errorf("expected %v, got %v", expected, attributes)
+	}
+}
+
+func TestAccMcmaResourceDataSource_basic(t *testing.T) {
+	resourceName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaResourceDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaResourceDataSource(resourceName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrPair("data.mcma_resource.bm_content", "id", "mcma_resource.bm_content_"+resourceName, "id"),
+						resource.TestCheckResourceAttr("data.mcma_resource.bm_content", "attributes.@type", "BMContent"),
+						resource.TestCheckResourceAttr("data.mcma_resource.bm_content", "attributes.status", "NEW"),
+						resource.TestCheckResourceAttrSet("data.mcma_resource.bm_content", "resource_json"),
+					),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+}
+
+func testAccountMcmaResourceDataSource(resourceName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_resource" "bm_content_%s" {
+  type = "BMContent"
+  resource_json = jsonencode({
+    status = "NEW"
+    metadata = {
+      name = "%s"
+      description = "Test asset generated by Terraform provider acceptance tests"
+	}
+  })
+}
+
+data "mcma_resource" "bm_content" {
+  type = "BMContent"
+  id = mcma_resource.bm_content_%s.id
+}
+`, providerConfig, resourceName, resourceName, resourceName)
+}
@@ -0,0 +1,100 @@
+package mcma
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceMcmaResources() *schema.Resource {
+	return &schema.Resource{
+		Description: "Queries arbitrary MCMA resources of a type through a service registered in the MCMA service registry",
+
+		ReadContext: dataSourceMcmaResourcesRead,
+
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Description: "The MCMA type of resource to query.",
+				Required:    true,
+			},
+			"query": {
+				Type:        schema.TypeMap,
+				Description: "Query parameters passed through to the endpoint of the service that handles the resource type.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Description: "The IDs of the matching resources.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"resources": {
+				Type:        schema.TypeList,
+				Description: "The matching resources.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: mcmaResourceDataSourceSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceMcmaResourcesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	resourceType := d.Get("type").(string)
+	query := make(map[string]string)
+	for key, value := range d.Get("query").(map[string]interface{}) {
+		query[key] = value.(string)
+	}
+
+	results, err := resourceManager.QueryResources(ctx, resourceType, query)
+	if err != nil {
+		return diag.Errorf("error querying resources of type %s: %s", resourceType, err)
+	}
+
+	ids := make([]interface{}, 0)
+	resources := make([]interface{}, 0)
+	for _, result := range results {
+		flattened, err := flattenMcmaResource(result)
+		if err != nil {
+			return diag.Errorf("error parsing json for resource of type %s with id %v: %s", resourceType, result["id"], err)
+		}
+		ids = append(ids, flattened["id"])
+		resources = append(resources, flattened)
+	}
+
+	var keys []string
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	idParts := []string{"resources", resourceType}
+	for _, key := range keys {
+		idParts = append(idParts, fmt.Sprintf("%s=%s", key, query[key]))
+	}
+
+	d.SetId(strings.Join(idParts, "|"))
+	if err := d.Set("ids", ids); err != nil {
+		return diag.Errorf("error setting ids: %s", err)
+	}
+	if err := d.Set("resources", resources); err != nil {
+		return diag.Errorf("error setting resources: %s", err)
+	}
+
+	return diag.Diagnostics{}
+}
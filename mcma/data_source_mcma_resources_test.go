@@ -0,0 +1,56 @@
+package mcma
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMcmaResourcesDataSource_basic(t *testing.T) {
+	resourceName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaResourceDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaResourcesDataSource(resourceName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckTypeSetElemAttrPair("data.mcma_resources.bm_contents", "ids.*", "mcma_resource.bm_content_"+resourceName, "id"),
+						resource.TestCheckTypeSetElemNestedAttrs("data.mcma_resources.bm_contents", "resources.*", map[string]string{
+							"type":              "BMContent",
+							"attributes.status": "NEW",
+						}),
+					),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+}
+
+func testAccountMcmaResourcesDataSource(resourceName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_resource" "bm_content_%s" {
+  type = "BMContent"
+  resource_json = jsonencode({
+    status = "NEW"
+    metadata = {
+      name = "%s"
+      description = "Test asset generated by Terraform provider acceptance tests"
+	}
+  })
+}
+
+data "mcma_resources" "bm_contents" {
+  type = "BMContent"
+  depends_on = [mcma_resource.bm_content_%s]
+}
+`, providerConfig, resourceName, resourceName, resourceName)
+}
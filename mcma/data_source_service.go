@@ -0,0 +1,181 @@
+package mcma
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+)
+
+// resourceEndpointDataSourceSchema returns the computed attributes of a resource endpoint as exposed by the service
+// data sources.
+func resourceEndpointDataSourceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"type": {
+			Type:        schema.TypeString,
+			Description: "The MCMA type of resource. This value will always be 'ResourceEndpoint'.",
+			Computed:    true,
+		},
+		"id": {
+			Type:        schema.TypeString,
+			Description: "The ID of the resource endpoint. MCMA IDs are always absolute urls.",
+			Computed:    true,
+		},
+		"date_created": {
+			Type:        schema.TypeString,
+			Description: "The date and time at which the resource endpoint data was created.",
+			Computed:    true,
+		},
+		"date_modified": {
+			Type:        schema.TypeString,
+			Description: "The date and time at which the resource endpoint data was last modified.",
+			Computed:    true,
+		},
+		"resource_type": {
+			Type:        schema.TypeString,
+			Description: "The type of MCMA resource this endpoint handles.",
+			Computed:    true,
+		},
+		"http_endpoint": {
+			Type:        schema.TypeString,
+			Description: "The url for the endpoint.",
+			Computed:    true,
+		},
+		"auth_type": {
+			Type:        schema.TypeString,
+			Description: "The type of authentication expected for this endpoint, if different than the auth type of the service.",
+			Computed:    true,
+		},
+	}
+}
+
+func dataSourceService() *schema.Resource {
+	return &schema.Resource{
+		Description: "Looks up service data registered in an MCMA Service Registry by ID or by name",
+
+		ReadContext: dataSourceServiceRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Description:  "The ID of the service. MCMA IDs are always absolute urls. Exactly one of id or name must be specified.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Description:  "The name of the service. It is an error if more than one service has this name. Exactly one of id or name must be specified.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Description: "The MCMA type of resource. This value will always be 'Service'.",
+				Computed:    true,
+			},
+			"date_created": {
+				Type:        schema.TypeString,
+				Description: "The date and time at which the service data was created.",
+				Computed:    true,
+			},
+			"date_modified": {
+				Type:        schema.TypeString,
+				Description: "The date and time at which the service data was last modified.",
+				Computed:    true,
+			},
+			"auth_type": {
+				Type:        schema.TypeString,
+				Description: "The type of authentication the service uses, e.g. AWS4",
+				Computed:    true,
+			},
+			"job_type": {
+				Type:        schema.TypeString,
+				Description: "The type of job the service processes, if any.",
+				Computed:    true,
+			},
+			"resource": {
+				Type:        schema.TypeList,
+				Description: "The resource endpoints of the service.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: resourceEndpointDataSourceSchema(),
+				},
+			},
+			"http_endpoints": {
+				Type:        schema.TypeMap,
+				Description: "The url of the endpoint for each resource type handled by the service. If the service has more than one endpoint for a resource type, the first one is used.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"job_profile_ids": {
+				Type:        schema.TypeList,
+				Description: "The list of IDs for job profiles that can be processed by this service.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServiceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	var service mcmamodel.Service
+	if serviceId := d.Get("id").(string); serviceId != "" {
+		resource, err := resourceManager.Get(ctx, reflect.TypeOf(mcmamodel.Service{}), serviceId)
+		if err != nil {
+			return diag.Errorf("error getting service with id %s: %s", serviceId, err)
+		}
+		if resource == nil {
+			return diag.Errorf("service with id %s not found", serviceId)
+		}
+		service = resource.(mcmamodel.Service)
+	} else {
+		name := d.Get("name").(string)
+		found, di := getServiceByName(ctx, resourceManager, name)
+		if di != nil {
+			return di
+		}
+		if found == nil {
+			return diag.Errorf("service with name %s not found", name)
+		}
+		service = *found
+	}
+
+	d.SetId(service.Id)
+	_ = d.Set("type", service.Type)
+	_ = d.Set("date_created", formatDate(service.DateCreated))
+	_ = d.Set("date_modified", formatDate(service.DateModified))
+	_ = d.Set("name", service.Name)
+	_ = d.Set("auth_type", service.AuthType)
+	_ = d.Set("job_type", service.JobType)
+	_ = d.Set("job_profile_ids", service.JobProfileIds)
+
+	if err := d.Set("resource", flattenServiceResources(service)); err != nil {
+		return diag.Errorf("error setting resources for service with id %s: %s", service.Id, err)
+	}
+
+	httpEndpoints := make(map[string]interface{})
+	for _, resourceEndpoint := range service.Resources {
+		if _, found := httpEndpoints[resourceEndpoint.ResourceType]; !found {
+			httpEndpoints[resourceEndpoint.ResourceType] = resourceEndpoint.HttpEndpoint
+		}
+	}
+	if err := d.Set("http_endpoints", httpEndpoints); err != nil {
+		return diag.Errorf("error setting http_endpoints for service with id %s: %s", service.Id, err)
+	}
+
+	return diag.Diagnostics{}
+}
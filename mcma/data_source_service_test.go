@@ -0,0 +1,64 @@
+package mcma
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMcmaServiceDataSource_basic(t *testing.T) {
+	serviceName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaServiceDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaServiceDataSource(serviceName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrPair("data.mcma_service.by_name", "id", "mcma_service.service_"+serviceName, "id"),
+						resource.TestCheckResourceAttrPair("data.mcma_service.by_id", "name", "mcma_service.service_"+serviceName, "name"),
+						resource.TestCheckResourceAttr("data.mcma_service.by_name", "job_type", "AmeJob"),
+						resource.TestCheckResourceAttr("data.mcma_service.by_name", "resource.#", "2"),
+						resource.TestCheckResourceAttr("data.mcma_service.by_name", "http_endpoints.JobAssignment", "https://some.endpoint.com/api/job-assignments"),
+						resource.TestCheckResourceAttr("data.mcma_service.by_id", "http_endpoints.Job", "https://some.endpoint.com/api/jobs"),
+					),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+	resource.Test(t, createTestCase(getMcmaApiKeyProviderConfigFromEnvVars()))
+}
+
+func testAccountMcmaServiceDataSource(serviceName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_service" "service_%s" {
+  name = "%s"
+  auth_type = "AWS4"
+  job_type = "AmeJob"
+  resource {
+	resource_type = "JobAssignment"
+	http_endpoint = "https://some.endpoint.com/api/job-assignments"
+  }
+  resource {
+	resource_type = "Job"
+	http_endpoint = "https://some.endpoint.com/api/jobs"
+  }
+}
+
+data "mcma_service" "by_name" {
+  name = mcma_service.service_%s.name
+}
+
+data "mcma_service" "by_id" {
+  id = mcma_service.service_%s.id
+}
+`, providerConfig, serviceName, serviceName, serviceName, serviceName)
+}
@@ -0,0 +1,226 @@
+package mcma
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+)
+
+func dataSourceServices() *schema.Resource {
+	return &schema.Resource{
+		Description: "Lists services registered in an MCMA Service Registry, optionally filtered",
+
+		ReadContext: dataSourceServicesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Description:  "A regular expression that the name of a service must match.",
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"job_type": {
+				Type:        schema.TypeString,
+				Description: "The type of job that a service must process, e.g. AmeJob.",
+				Optional:    true,
+			},
+			"auth_type": {
+				Type:        schema.TypeString,
+				Description: "The auth type that a service must use.",
+				Optional:    true,
+			},
+			"resource_type": {
+				Type:        schema.TypeString,
+				Description: "A type of MCMA resource that a service must have an endpoint for.",
+				Optional:    true,
+			},
+			"job_profile_id": {
+				Type:        schema.TypeString,
+				Description: "The ID of a job profile that a service must support.",
+				Optional:    true,
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Description: "The IDs of the matching services.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"services": {
+				Type:        schema.TypeList,
+				Description: "The matching services.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Description: "The MCMA type of resource. This value will always be 'Service'.",
+							Computed:    true,
+						},
+						"id": {
+							Type:        schema.TypeString,
+							Description: "The ID of the service. MCMA IDs are always absolute urls.",
+							Computed:    true,
+						},
+						"date_created": {
+							Type:        schema.TypeString,
+							Description: "The date and time at which the service data was created.",
+							Computed:    true,
+						},
+						"date_modified": {
+							Type:        schema.TypeString,
+							Description: "The date and time at which the service data was last modified.",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the service.",
+							Computed:    true,
+						},
+						"auth_type": {
+							Type:        schema.TypeString,
+							Description: "The type of authentication the service uses, e.g. AWS4",
+							Computed:    true,
+						},
+						"job_type": {
+							Type:        schema.TypeString,
+							Description: "The type of job the service processes, if any.",
+							Computed:    true,
+						},
+						"resource": {
+							Type:        schema.TypeList,
+							Description: "The resource endpoints of the service.",
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: resourceEndpointDataSourceSchema(),
+							},
+						},
+						"job_profile_ids": {
+							Type:        schema.TypeList,
+							Description: "The list of IDs for job profiles that can be processed by this service.",
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func flattenService(service mcmamodel.Service) map[string]interface{} {
+	var jobProfileIds []interface{}
+	for _, jobProfileId := range service.JobProfileIds {
+		jobProfileIds = append(jobProfileIds, jobProfileId)
+	}
+
+	return map[string]interface{}{
+		"type":            service.Type,
+		"id":              service.Id,
+		"date_created":    formatDate(service.DateCreated),
+		"date_modified":   formatDate(service.DateModified),
+		"name":            service.Name,
+		"auth_type":       service.AuthType,
+		"job_type":        service.JobType,
+		"resource":        flattenServiceResources(service),
+		"job_profile_ids": jobProfileIds,
+	}
+}
+
+func serviceMatches(service mcmamodel.Service, nameRegex *regexp.Regexp, jobType, authType, resourceType, jobProfileId string) bool {
+	if nameRegex != nil && !nameRegex.MatchString(service.Name) {
+		return false
+	}
+	if jobType != "" && service.JobType != jobType {
+		return false
+	}
+	if authType != "" && service.AuthType != authType {
+		return false
+	}
+	if resourceType != "" {
+		found := false
+		for _, resourceEndpoint := range service.Resources {
+			if resourceEndpoint.ResourceType == resourceType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if jobProfileId != "" {
+		found := false
+		for _, id := range service.JobProfileIds {
+			if id == jobProfileId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func dataSourceServicesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	var nameRegex *regexp.Regexp
+	if v := d.Get("name_regex").(string); v != "" {
+		nameRegex = regexp.MustCompile(v)
+	}
+	jobType := d.Get("job_type").(string)
+	authType := d.Get("auth_type").(string)
+	resourceType := d.Get("resource_type").(string)
+	jobProfileId := d.Get("job_profile_id").(string)
+
+	// filter on the server where the registry supports it, and on the client for everything else
+	filter := make(map[string]string)
+	if jobType != "" {
+		filter["jobType"] = jobType
+	}
+	if authType != "" {
+		filter["authType"] = authType
+	}
+
+	results, err := resourceManager.Query(ctx, reflect.TypeOf(mcmamodel.Service{}), filter)
+	if err != nil {
+		return diag.Errorf("error querying services: %s", err)
+	}
+
+	ids := make([]interface{}, 0)
+	services := make([]interface{}, 0)
+	for _, result := range results {
+		service := result.(mcmamodel.Service)
+		if !serviceMatches(service, nameRegex, jobType, authType, resourceType, jobProfileId) {
+			continue
+		}
+		ids = append(ids, service.Id)
+		services = append(services, flattenService(service))
+	}
+
+	d.SetId(strings.Join([]string{"services", d.Get("name_regex").(string), jobType, authType, resourceType, jobProfileId}, "|"))
+	if err := d.Set("ids", ids); err != nil {
+		return diag.Errorf("error setting ids: %s", err)
+	}
+	if err := d.Set("services", services); err != nil {
+		return diag.Errorf("error setting services: %s", err)
+	}
+
+	return diag.Diagnostics{}
+}
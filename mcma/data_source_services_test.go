@@ -0,0 +1,88 @@
+package mcma
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMcmaServicesDataSource_filtered(t *testing.T) {
+	serviceName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaServiceDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaServicesDataSource(serviceName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("data.mcma_services.by_name", "services.#", "2"),
+						resource.TestCheckResourceAttr("data.mcma_services.by_job_type", "services.#", "1"),
+						resource.TestCheckResourceAttrPair("data.mcma_services.by_job_type", "ids.0", "mcma_service.ame_"+serviceName, "id"),
+						resource.TestCheckResourceAttr("data.mcma_services.by_job_type", "services.0.resource.#", "2"),
+						resource.TestCheckResourceAttr("data.mcma_services.by_resource_type", "services.#", "1"),
+						resource.TestCheckResourceAttrPair("data.mcma_services.by_resource_type", "services.0.name", "mcma_service.ame_"+serviceName, "name"),
+					),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+	resource.Test(t, createTestCase(getMcmaApiKeyProviderConfigFromEnvVars()))
+}
+
+func testAccountMcmaServicesDataSource(serviceName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_service" "ame_%s" {
+  name = "%s ame"
+  auth_type = "AWS4"
+  job_type = "AmeJob"
+  resource {
+	resource_type = "JobAssignment"
+	http_endpoint = "https://some.endpoint.com/api/job-assignments"
+  }
+  resource {
+	resource_type = "Job"
+	http_endpoint = "https://some.endpoint.com/api/jobs"
+  }
+}
+
+resource "mcma_service" "transform_%s" {
+  name = "%s transform"
+  auth_type = "AWS4"
+  job_type = "TransformJob"
+  resource {
+	resource_type = "JobAssignment"
+	http_endpoint = "https://some.endpoint.com/api/job-assignments"
+  }
+}
+
+data "mcma_services" "by_name" {
+  name_regex = "^%s "
+  depends_on = [mcma_service.ame_%s, mcma_service.transform_%s]
+}
+
+data "mcma_services" "by_job_type" {
+  name_regex = "^%s "
+  job_type = "AmeJob"
+  depends_on = [mcma_service.ame_%s, mcma_service.transform_%s]
+}
+
+data "mcma_services" "by_resource_type" {
+  name_regex = "^%s "
+  resource_type = "Job"
+  depends_on = [mcma_service.ame_%s, mcma_service.transform_%s]
+}
+`, providerConfig,
+		serviceName, serviceName,
+		serviceName, serviceName,
+		serviceName, serviceName, serviceName,
+		serviceName, serviceName, serviceName,
+		serviceName, serviceName, serviceName)
+}
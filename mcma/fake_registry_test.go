@@ -0,0 +1,50 @@
+package mcma
+
+import (
+	"sync"
+
+	"github.com/ebu/terraform-provider-mcma/mcmatest"
+)
+
+// fakeJobType is the type of the jobs run by the fake registries used by the acceptance tests, and fakeJobProfileName
+// the name of the job profile seeded for them.
+const (
+	fakeJobType        = "FakeJob"
+	fakeJobProfileName = "FakeJobProfile"
+)
+
+// newDefaultFakeRegistry returns a fake registry with the resource endpoints and job profile used by the acceptance
+// tests.
+func newDefaultFakeRegistry(authType string) *mcmatest.Registry {
+	r := mcmatest.NewRegistry(authType)
+	r.AddResourceEndpoint("BMContent", "/api/bm-contents")
+	r.AddJobEndpoint(fakeJobType, "/api/jobs", func(jobInput map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"echo": jobInput}, nil
+	})
+	if _, err := r.Seed("JobProfile", map[string]interface{}{
+		"name":            fakeJobProfileName,
+		"inputParameters": []interface{}{map[string]interface{}{"parameterName": "inputFile", "parameterType": "string"}},
+	}); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+var (
+	fakeRegistriesMutex sync.Mutex
+	fakeRegistries      = make(map[string]*mcmatest.Registry)
+)
+
+// getFakeRegistry returns the fake registry shared by the acceptance tests that use the given auth type. It is
+// started on first use and runs until the tests finish.
+func getFakeRegistry(authType string) *mcmatest.Registry {
+	fakeRegistriesMutex.Lock()
+	defer fakeRegistriesMutex.Unlock()
+
+	if r, found := fakeRegistries[authType]; found {
+		return r
+	}
+	r := newDefaultFakeRegistry(authType)
+	fakeRegistries[authType] = r
+	return r
+}
@@ -0,0 +1,177 @@
+package mcma
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// tlsEnvVars are the MCMA_TLS_* environment variables from which the tls block is built when it is not specified.
+var tlsEnvVars = map[string]string{
+	"ca_cert_file": "MCMA_TLS_CA_CERT_FILE",
+	"ca_cert_pem":  "MCMA_TLS_CA_CERT_PEM",
+	"client_cert":  "MCMA_TLS_CLIENT_CERT",
+	"client_key":   "MCMA_TLS_CLIENT_KEY",
+}
+
+func tlsResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Description: "The path to a PEM-encoded CA certificate bundle used to verify server certificates, in addition to the system CAs. Set from the MCMA_TLS_CA_CERT_FILE environment variable when no tls block is specified.",
+				Optional:    true,
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Description: "A PEM-encoded CA certificate bundle used to verify server certificates, in addition to the system CAs. Set from the MCMA_TLS_CA_CERT_PEM environment variable when no tls block is specified.",
+				Optional:    true,
+			},
+			"client_cert": {
+				Type:        schema.TypeString,
+				Description: "A PEM-encoded client certificate to present to servers requiring mutual TLS. Requires that client_key also be specified. Set from the MCMA_TLS_CLIENT_CERT environment variable when no tls block is specified.",
+				Optional:    true,
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Description: "The PEM-encoded private key for the client certificate. Requires that client_cert also be specified. Set from the MCMA_TLS_CLIENT_KEY environment variable when no tls block is specified.",
+				Optional:    true,
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Description: "Skip verification of server certificates. This should only be used for local development.",
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func getTLSConfig(tlsData map[string]interface{}) (*tls.Config, diag.Diagnostics) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	caCertFile, _ := tlsData["ca_cert_file"].(string)
+	caCertPem, _ := tlsData["ca_cert_pem"].(string)
+	if caCertFile != "" || caCertPem != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if caCertFile != "" {
+			caCertBytes, err := os.ReadFile(caCertFile)
+			if err != nil {
+				return nil, diag.Diagnostics{
+					diag.Diagnostic{
+						Severity:      diag.Error,
+						Summary:       "Failed to read CA certificate file.",
+						Detail:        err.Error(),
+						AttributePath: cty.GetAttrPath("tls").IndexInt(0).GetAttr("ca_cert_file"),
+					},
+				}
+			}
+			if !rootCAs.AppendCertsFromPEM(caCertBytes) {
+				return nil, diag.Diagnostics{
+					diag.Diagnostic{
+						Severity:      diag.Error,
+						Summary:       "Invalid CA certificate file.",
+						Detail:        "No PEM-encoded certificates found in " + caCertFile,
+						AttributePath: cty.GetAttrPath("tls").IndexInt(0).GetAttr("ca_cert_file"),
+					},
+				}
+			}
+		}
+		if caCertPem != "" && !rootCAs.AppendCertsFromPEM([]byte(caCertPem)) {
+			return nil, diag.Diagnostics{
+				diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Invalid CA certificate.",
+					Detail:        "No PEM-encoded certificates found in ca_cert_pem",
+					AttributePath: cty.GetAttrPath("tls").IndexInt(0).GetAttr("ca_cert_pem"),
+				},
+			}
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	clientCert, _ := tlsData["client_cert"].(string)
+	clientKey, _ := tlsData["client_key"].(string)
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, diag.Diagnostics{
+				diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Incomplete client certificate.",
+					Detail:        "Both client_cert and client_key must be specified to use a client certificate.",
+					AttributePath: cty.GetAttrPath("tls").IndexInt(0),
+				},
+			}
+		}
+		certificate, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, diag.Diagnostics{
+				diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Invalid client certificate.",
+					Detail:        err.Error(),
+					AttributePath: cty.GetAttrPath("tls").IndexInt(0).GetAttr("client_cert"),
+				},
+			}
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if insecureSkipVerify, ok := tlsData["insecure_skip_verify"].(bool); ok && insecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	return tlsConfig, nil
+}
+
+func newHttpTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
+
+// newHttpClient builds the HTTP client for the provider configuration, with its TLS and retry settings. It is used
+// for every HTTP call the provider makes, through the resource manager, the authenticators and directly. The default
+// HTTP client and transport of the process are left untouched, so that provider configurations running in the same
+// process, e.g. in acceptance tests, do not affect each other or any other client.
+func newHttpClient(d *schema.ResourceData) (*http.Client, diag.Diagnostics) {
+	var tlsData map[string]interface{}
+	if blocks := d.Get("tls").([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+		tlsData = blocks[0].(map[string]interface{})
+	} else {
+		tlsData = getBlockDataFromEnvVars(tlsEnvVars)
+	}
+
+	var tlsConfig *tls.Config
+	if tlsData != nil {
+		var di diag.Diagnostics
+		tlsConfig, di = getTLSConfig(tlsData)
+		if di != nil {
+			return nil, di
+		}
+	}
+
+	policy, di := getRetryPolicy(d)
+	if di != nil {
+		return nil, di
+	}
+
+	return &http.Client{
+		Transport: &retryTransport{
+			next:   newHttpTransport(tlsConfig),
+			policy: policy,
+		},
+	}, nil
+}
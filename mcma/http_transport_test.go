@@ -0,0 +1,138 @@
+package mcma
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func generateTestClientCertificate(t *testing.T) (certPem string, keyPem string, cert *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform-provider-mcma-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPem = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPem = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+	return certPem, keyPem, cert
+}
+
+func newTestMutualTLSServer(t *testing.T, clientCert *x509.Certificate) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	return server
+}
+
+func serverCertPem(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func TestHttpTransport_mutualTLS(t *testing.T) {
+	clientCertPem, clientKeyPem, clientCert := generateTestClientCertificate(t)
+	server := newTestMutualTLSServer(t, clientCert)
+	defer server.Close()
+
+	tlsConfig, d := getTLSConfig(map[string]interface{}{
+		"ca_cert_pem": serverCertPem(server),
+		"client_cert": clientCertPem,
+		"client_key":  clientKeyPem,
+	})
+	if d != nil {
+		t.Fatalf("unexpected error building TLS config: %v", d)
+	}
+
+	client := &http.Client{Transport: newHttpTransport(tlsConfig)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error calling server with client certificate: %s", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestHttpTransport_noClientCertificate(t *testing.T) {
+	_, _, clientCert := generateTestClientCertificate(t)
+	server := newTestMutualTLSServer(t, clientCert)
+	defer server.Close()
+
+	tlsConfig, d := getTLSConfig(map[string]interface{}{
+		"ca_cert_pem": serverCertPem(server),
+	})
+	if d != nil {
+		t.Fatalf("unexpected error building TLS config: %v", d)
+	}
+
+	client := &http.Client{Transport: newHttpTransport(tlsConfig)}
+	if resp, err := client.Get(server.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("expected error calling server requiring a client certificate without one")
+	}
+}
+
+func TestHttpTransport_unknownCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newHttpTransport(nil)}
+	if resp, err := client.Get(server.URL); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("expected error calling server with certificate from unknown CA")
+	}
+
+	tlsConfig, d := getTLSConfig(map[string]interface{}{
+		"insecure_skip_verify": true,
+	})
+	if d != nil {
+		t.Fatalf("unexpected error building TLS config: %v", d)
+	}
+	client = &http.Client{Transport: newHttpTransport(tlsConfig)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error calling server with insecure_skip_verify: %s", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestGetTLSConfig_incompleteClientCertificate(t *testing.T) {
+	clientCertPem, _, _ := generateTestClientCertificate(t)
+	if _, d := getTLSConfig(map[string]interface{}{"client_cert": clientCertPem}); !d.HasError() {
+		t.Fatal("expected error when client_key is not specified")
+	}
+}
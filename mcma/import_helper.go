@@ -0,0 +1,35 @@
+package mcma
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// isAbsoluteUrl reports whether an import ID is an MCMA ID, which is always an absolute url, rather than a name.
+func isAbsoluteUrl(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+// diagsToError converts the error diagnostics returned by the helpers shared with the CRUD functions into an error,
+// as importers cannot return diagnostics.
+func diagsToError(diags diag.Diagnostics) error {
+	var messages []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		if d.Detail != "" {
+			messages = append(messages, d.Summary+": "+d.Detail)
+		} else {
+			messages = append(messages, d.Summary)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
@@ -0,0 +1,21 @@
+package mcma
+
+import (
+	"testing"
+)
+
+func TestIsAbsoluteUrl(t *testing.T) {
+	cases := map[string]bool{
+		"https://service-registry.mcma.io/api/services/12345": true,
+		"http://localhost:8080/job-profiles/1":                true,
+		"MediaInfo AME Service":                               false,
+		"ExtractTechnicalMetadata":                            false,
+		"/api/services/12345":                                 false,
+		"BMContent,https://service.mcma.io/bm-contents/1":     false,
+	}
+	for s, expected := range cases {
+		if actual := isAbsoluteUrl(s); actual != expected {
+			t.Errorf("isAbsoluteUrl(%q): expected %t, got %t", s, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package mcma
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// jobInputTypeMismatch describes an input parameter whose value does not have the type declared in the job profile.
+type jobInputTypeMismatch struct {
+	Name         string
+	ExpectedType string
+	ActualType   string
+}
+
+// jobInputValidation is the result of checking the input of a job against the input parameters of its job profile.
+type jobInputValidation struct {
+	MissingParameters []string
+	UnknownParameters []string
+	TypeMismatches    []jobInputTypeMismatch
+	// Errors describes every problem found, including values that are not one of the allowed values of a parameter
+	Errors []string
+}
+
+func (v jobInputValidation) valid() bool {
+	return len(v.Errors) == 0
+}
+
+// validateJobInput checks the input of a job against the input parameters of a job profile. The @type of the job
+// input, if present, is not a parameter. Unknown parameters are only reported as errors if allowUnknown is false.
+//
+// Parameter types are either primitive JSON types (string, number, integer, boolean, object, array or any), arrays of
+// them written as e.g. string[], or MCMA types. Values of MCMA types must be objects whose @type is the declared type,
+// optionally namespaced, or one of its known subtypes, e.g. an S3Locator for a Locator, or absolute urls referencing
+// such a resource.
+func validateJobInput(jobProfile jobProfileDocument, jobInput map[string]interface{}, allowUnknown bool) jobInputValidation {
+	var result jobInputValidation
+
+	parameters := make(map[string]jobParameterDocument)
+	for _, p := range jobProfile.InputParameters {
+		parameters[p.ParameterName] = p
+		if _, found := jobInput[p.ParameterName]; !found {
+			result.MissingParameters = append(result.MissingParameters, p.ParameterName)
+			result.Errors = append(result.Errors, fmt.Sprintf("missing required input parameter %s of type %s", p.ParameterName, p.ParameterType))
+		}
+	}
+	for _, p := range jobProfile.OptionalInputParameters {
+		parameters[p.ParameterName] = p
+	}
+
+	names := make([]string, 0, len(jobInput))
+	for name := range jobInput {
+		if name != "@type" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := jobInput[name]
+		p, found := parameters[name]
+		if !found {
+			result.UnknownParameters = append(result.UnknownParameters, name)
+			if !allowUnknown {
+				result.Errors = append(result.Errors, fmt.Sprintf("unknown input parameter %s, job profile %s has no such parameter", name, jobProfile.Name))
+			}
+			continue
+		}
+
+		if !jobParameterValueHasType(value, p.ParameterType) {
+			actualType := jobParameterValueType(value)
+			result.TypeMismatches = append(result.TypeMismatches, jobInputTypeMismatch{
+				Name:         name,
+				ExpectedType: p.ParameterType,
+				ActualType:   actualType,
+			})
+			result.Errors = append(result.Errors, fmt.Sprintf("input parameter %s must be of type %s, got %s", name, p.ParameterType, actualType))
+			continue
+		}
+
+		if len(p.AllowedValues) > 0 && !isAllowedJobParameterValue(value, p.AllowedValues) {
+			s, _ := jsonValueToString(value)
+			result.Errors = append(result.Errors, fmt.Sprintf("input parameter %s must be one of [%s], got %s", name, strings.Join(p.AllowedValues, ", "), s))
+		}
+	}
+
+	return result
+}
+
+// jobParameterValueType returns the type of a decoded JSON value, as it would be declared for a parameter.
+func jobParameterValueType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		if t, ok := v["@type"].(string); ok && t != "" {
+			return t
+		}
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func jobParameterValueHasType(value interface{}, parameterType string) bool {
+	if elementType := strings.TrimSuffix(parameterType, "[]"); elementType != parameterType {
+		elements, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, element := range elements {
+			if !jobParameterValueHasType(element, elementType) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch strings.ToLower(parameterType) {
+	case "", "any":
+		return true
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number", "float", "double":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer", "int":
+		n, ok := value.(json.Number)
+		return ok && isIntegerJsonNumber(n)
+	case "boolean", "bool":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array", "list":
+		_, ok := value.([]interface{})
+		return ok
+	}
+
+	// MCMA types
+	switch v := value.(type) {
+	case string:
+		return isAbsoluteUrl(v)
+	case map[string]interface{}:
+		t, _ := v["@type"].(string)
+		return mcmaTypeMatches(t, parameterType)
+	default:
+		return false
+	}
+}
+
+// mcmaSubtypes lists the MCMA types that are accepted for a parameter of a more general type, e.g. the locators of the
+// storage services for a parameter of type Locator.
+var mcmaSubtypes = map[string][]string{
+	"Locator": {
+		"S3Locator",
+		"AwsS3FileLocator",
+		"AwsS3FolderLocator",
+		"AzureBlobStorageFileLocator",
+		"AzureBlobStorageFolderLocator",
+		"GoogleCloudStorageFileLocator",
+		"GoogleCloudStorageFolderLocator",
+	},
+}
+
+// mcmaTypeMatches returns whether the @type of a value is the MCMA type of a parameter or one of its subtypes. The type
+// may be qualified with a namespace, e.g. urn:mcma:Locator or Mcma.Locator, but its name must match in full, so that
+// e.g. BMEssence is not taken for an Essence.
+func mcmaTypeMatches(valueType string, parameterType string) bool {
+	if valueType == parameterType {
+		return true
+	}
+	name := valueType[strings.LastIndexAny(valueType, ":.")+1:]
+	if name == parameterType {
+		return true
+	}
+	for _, subtype := range mcmaSubtypes[parameterType] {
+		if name == subtype {
+			return true
+		}
+	}
+	return false
+}
+
+// isIntegerJsonNumber returns whether a JSON number has no fractional part, e.g. 5000, 5e3 or 5000.0, however large it
+// is.
+func isIntegerJsonNumber(n json.Number) bool {
+	f, ok := new(big.Float).SetString(n.String())
+	return ok && f.IsInt()
+}
+
+// isAllowedJobParameterValue returns whether a value is one of the allowed values of a parameter. Numbers are compared
+// by value, so that 5000.0 is allowed if 5000 is.
+func isAllowedJobParameterValue(value interface{}, allowedValues []string) bool {
+	s, err := jsonValueToString(value)
+	if err != nil {
+		return false
+	}
+	n, isNumber := value.(json.Number)
+	for _, allowed := range allowedValues {
+		if s == allowed || isNumber && jsonNumbersEqual(n, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonNumbersEqual(n json.Number, s string) bool {
+	a, ok := new(big.Float).SetString(n.String())
+	if !ok {
+		return false
+	}
+	b, ok := new(big.Float).SetString(s)
+	return ok && a.Cmp(b) == 0
+}
+
+// decodeJobInput decodes the JSON of a job input, keeping numbers as json.Number so that they are validated and
+// encoded again exactly as they were written, whatever their size or precision.
+func decodeJobInput(s string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	var jobInput map[string]interface{}
+	if err := decoder.Decode(&jobInput); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the job input object")
+	}
+	if jobInput == nil {
+		return nil, fmt.Errorf("job input must be a JSON object")
+	}
+	return jobInput, nil
+}
+
+// normalizeJobInput returns the JSON of a job input with sorted keys and without insignificant whitespace. Numbers
+// decoded by decodeJobInput keep the literal they were written with.
+func normalizeJobInput(jobInput map[string]interface{}) (string, error) {
+	jsonBytes, err := json.Marshal(jobInput)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
@@ -0,0 +1,153 @@
+package mcma
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateJobInput(t *testing.T) {
+	jobProfile := jobProfileDocument{
+		Name: "TranscodeFile",
+		InputParameters: []jobParameterDocument{
+			{ParameterName: "inputFile", ParameterType: "Locator"},
+			{ParameterName: "format", ParameterType: "string", AllowedValues: []string{"mp4", "mov"}},
+			{ParameterName: "outputLocation", ParameterType: "Locator"},
+		},
+		OptionalInputParameters: []jobParameterDocument{
+			{ParameterName: "bitrate", ParameterType: "integer"},
+			{ParameterName: "tags", ParameterType: "string[]"},
+			{ParameterName: "bmContent", ParameterType: "BMContent"},
+			{ParameterName: "frameCount", ParameterType: "integer"},
+			{ParameterName: "channels", ParameterType: "number", AllowedValues: []string{"2", "6"}},
+		},
+	}
+
+	cases := map[string]struct {
+		input        string
+		allowUnknown bool
+		expected     jobInputValidation
+	}{
+		"valid": {
+			input: `{
+				"@type": "JobParameterBag",
+				"inputFile": {"@type": "S3Locator", "url": "s3://bucket/file.mp4"},
+				"format": "mp4",
+				"outputLocation": {"@type": "S3Locator", "url": "s3://bucket/output/"},
+				"bitrate": 5000,
+				"tags": ["a", "b"],
+				"bmContent": "https://service.mcma.io/api/bm-contents/1",
+				"frameCount": 12345678901234567890123,
+				"channels": 6.0
+			}`,
+		},
+		"integers written with an exponent": {
+			input: `{
+				"inputFile": {"@type": "S3Locator", "url": "s3://bucket/file.mp4"},
+				"format": "mp4",
+				"outputLocation": {"@type": "S3Locator", "url": "s3://bucket/output/"},
+				"bitrate": 5e3,
+				"frameCount": 1.5E2
+			}`,
+		},
+		"missing, unknown and mismatched parameters": {
+			input: `{
+				"inputFile": {"url": "s3://bucket/file.mp4"},
+				"format": "avi",
+				"bitrate": 5000.5,
+				"tags": ["a", 1],
+				"colour": "red"
+			}`,
+			expected: jobInputValidation{
+				MissingParameters: []string{"outputLocation"},
+				UnknownParameters: []string{"colour"},
+				TypeMismatches: []jobInputTypeMismatch{
+					{Name: "bitrate", ExpectedType: "integer", ActualType: "number"},
+					{Name: "inputFile", ExpectedType: "Locator", ActualType: "object"},
+					{Name: "tags", ExpectedType: "string[]", ActualType: "array"},
+				},
+				Errors: []string{
+					"missing required input parameter outputLocation of type Locator",
+					"input parameter bitrate must be of type integer, got number",
+					"unknown input parameter colour, job profile TranscodeFile has no such parameter",
+					"input parameter format must be one of [mp4, mov], got avi",
+					"input parameter inputFile must be of type Locator, got object",
+					"input parameter tags must be of type string[], got array",
+				},
+			},
+		},
+		"unknown parameters allowed": {
+			input: `{
+				"inputFile": {"@type": "Locator", "url": "s3://bucket/file.mp4"},
+				"format": "mov",
+				"outputLocation": {"@type": "S3Locator", "url": "s3://bucket/output/"},
+				"colour": "red"
+			}`,
+			allowUnknown: true,
+			expected: jobInputValidation{
+				UnknownParameters: []string{"colour"},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			input, err := decodeJobInput(c.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := validateJobInput(jobProfile, input, c.allowUnknown)
+			if !reflect.DeepEqual(result, c.expected) {
+				t.Errorf("expected %+v, got %+v", c.expected, result)
+			}
+			if result.valid() != (len(c.expected.Errors) == 0) {
+				t.Errorf("expected valid to be %t", len(c.expected.Errors) == 0)
+			}
+		})
+	}
+}
+
+func TestJobParameterValueHasType(t *testing.T) {
+	cases := []struct {
+		valueType     string
+		parameterType string
+		expected      bool
+	}{
+		{"Locator", "Locator", true},
+		{"S3Locator", "Locator", true},
+		{"AwsS3FileLocator", "Locator", true},
+		{"urn:mcma:Locator", "Locator", true},
+		{"Mcma.BMEssence", "BMEssence", true},
+		{"BMEssence", "Essence", false},
+		{"urn:mcma:BMEssence", "Essence", false},
+		{"MyLocator", "Locator", false},
+		{"Locator", "S3Locator", false},
+	}
+	for _, c := range cases {
+		value := map[string]interface{}{"@type": c.valueType}
+		if result := jobParameterValueHasType(value, c.parameterType); result != c.expected {
+			t.Errorf("expected value of type %s to match parameter type %s to be %t, got %t", c.valueType, c.parameterType, c.expected, result)
+		}
+	}
+}
+
+func TestNormalizeJobInput(t *testing.T) {
+	input, err := decodeJobInput(`{ "b": 1.50, "a": {"d": true, "c": null}, "e": 12345678901234567890123 }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalized, err := normalizeJobInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"a":{"c":null,"d":true},"b":1.50,"e":12345678901234567890123}`; normalized != expected {
+		t.Errorf("expected %s, got %s", expected, normalized)
+	}
+}
+
+func TestDecodeJobInput(t *testing.T) {
+	for _, invalid := range []string{`[]`, `null`, `{"a": 1} {"b": 2}`, `{"a": 1`} {
+		if _, err := decodeJobInput(invalid); err == nil {
+			t.Errorf("expected error decoding %s", invalid)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package mcma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+)
+
+// jobParameterDocument is a job parameter as stored in a job profile document. Besides the name and type known to
+// mcmamodel.JobParameter, it holds the documentation of the parameter.
+type jobParameterDocument struct {
+	ParameterName string   `json:"parameterName"`
+	ParameterType string   `json:"parameterType"`
+	Description   string   `json:"description,omitempty"`
+	DefaultValue  string   `json:"defaultValue,omitempty"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+}
+
+// jobProfileDocument is a job profile as stored in the service registry. It is sent and received through the
+// untyped resource manager functions, as mcmamodel.JobProfile would drop the documentation of its parameters.
+type jobProfileDocument struct {
+	Type                    string                 `json:"@type"`
+	Id                      string                 `json:"id,omitempty"`
+	DateCreated             *time.Time             `json:"dateCreated,omitempty"`
+	DateModified            *time.Time             `json:"dateModified,omitempty"`
+	Name                    string                 `json:"name"`
+	InputParameters         []jobParameterDocument `json:"inputParameters,omitempty"`
+	OutputParameters        []jobParameterDocument `json:"outputParameters,omitempty"`
+	OptionalInputParameters []jobParameterDocument `json:"optionalInputParameters,omitempty"`
+	Custom                  map[string]interface{} `json:"custom,omitempty"`
+}
+
+// optionalDate returns nil for a zero date, so that it is left out of a document rather than sent as year 1.
+func optionalDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// dateValue returns the date of a document, or the zero date if the document has none.
+func dateValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func jobParameterDocumentsFromModel(parameters []mcmamodel.JobParameter) []jobParameterDocument {
+	var documents []jobParameterDocument
+	for _, parameter := range parameters {
+		documents = append(documents, jobParameterDocument{
+			ParameterName: parameter.ParameterName,
+			ParameterType: parameter.ParameterType,
+		})
+	}
+	return documents
+}
+
+// jobProfileDocumentFromModel converts a job profile returned by the typed resource manager functions, which has no
+// parameter documentation.
+func jobProfileDocumentFromModel(jobProfile mcmamodel.JobProfile) jobProfileDocument {
+	return jobProfileDocument{
+		Type:                    jobProfile.Type,
+		Id:                      jobProfile.Id,
+		DateCreated:             optionalDate(jobProfile.DateCreated),
+		DateModified:            optionalDate(jobProfile.DateModified),
+		Name:                    jobProfile.Name,
+		InputParameters:         jobParameterDocumentsFromModel(jobProfile.InputParameters),
+		OutputParameters:        jobParameterDocumentsFromModel(jobProfile.OutputParameters),
+		OptionalInputParameters: jobParameterDocumentsFromModel(jobProfile.OptionalInputParameters),
+		Custom:                  jobProfile.Custom,
+	}
+}
+
+func jobProfileDocumentFromMap(resource map[string]interface{}) (jobProfileDocument, error) {
+	var jobProfile jobProfileDocument
+	jsonBytes, err := json.Marshal(resource)
+	if err != nil {
+		return jobProfile, err
+	}
+	err = json.Unmarshal(jsonBytes, &jobProfile)
+	return jobProfile, err
+}
+
+func (jobProfile jobProfileDocument) toMap() (map[string]interface{}, error) {
+	jsonBytes, err := json.Marshal(jobProfile)
+	if err != nil {
+		return nil, err
+	}
+	resource := make(map[string]interface{})
+	err = json.Unmarshal(jsonBytes, &resource)
+	return resource, err
+}
+
+// getJobProfileDocument gets a job profile through the untyped resource manager functions, so that the documentation
+// of its parameters is read as well. It returns nil if the job profile does not exist.
+func getJobProfileDocument(ctx context.Context, resourceManager *mcmaclient.ResourceManager, jobProfileId string) (*jobProfileDocument, error) {
+	resource, err := resourceManager.GetResource(ctx, "JobProfile", jobProfileId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting job profile with id %s: %s", jobProfileId, err)
+	}
+	if resource == nil {
+		return nil, nil
+	}
+	jobProfile, err := jobProfileDocumentFromMap(resource)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing job profile with id %s: %s", jobProfileId, err)
+	}
+	return &jobProfile, nil
+}
@@ -0,0 +1,27 @@
+package mcma
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobProfileDocument_toMapWithoutDates(t *testing.T) {
+	resource, err := jobProfileDocument{Type: "JobProfile", Name: "ExtractTechnicalMetadata"}.toMap()
+	if err != nil {
+		t.Fatalf("error converting job profile: %s", err)
+	}
+	for _, key := range []string{"dateCreated", "dateModified"} {
+		if value, found := resource[key]; found {
+			t.Errorf("expected %s to be left out of a job profile without dates, got %v", key, value)
+		}
+	}
+
+	dateCreated := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	resource, err = jobProfileDocument{Type: "JobProfile", Name: "ExtractTechnicalMetadata", DateCreated: &dateCreated}.toMap()
+	if err != nil {
+		t.Fatalf("error converting job profile: %s", err)
+	}
+	if resource["dateCreated"] != "2022-03-01T10:00:00Z" {
+		t.Errorf("expected dateCreated 2022-03-01T10:00:00Z, got %v", resource["dateCreated"])
+	}
+}
@@ -0,0 +1,219 @@
+package mcma
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultIgnoredFields are the properties that an MCMA service sets on every resource it stores.
+var defaultIgnoredFields = []string{"dateCreated", "dateModified"}
+
+var jsonPathIndexRegexp = regexp.MustCompile(`\[(\*|\d+)\]`)
+
+// parseJsonPath splits a JSON path such as $.metadata.checksum, locators.*.url or $.locators[0].url into its segments.
+// A leading $ is optional and * matches every property of an object or every element of an array.
+func parseJsonPath(path string) ([]string, error) {
+	trimmed := jsonPathIndexRegexp.ReplaceAllString(path, ".$1")
+	trimmed = strings.TrimPrefix(strings.TrimPrefix(trimmed, "$"), ".")
+	if trimmed == "" {
+		return nil, fmt.Errorf("json path %q is empty", path)
+	}
+	segments := strings.Split(trimmed, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("json path %q contains an empty segment", path)
+		}
+	}
+	return segments, nil
+}
+
+func validateJsonPath(v interface{}, k string) ([]string, []error) {
+	if _, err := parseJsonPath(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// removeJsonPath removes the value at the given path from a decoded JSON document, if it is present.
+func removeJsonPath(doc interface{}, segments []string) {
+	if len(segments) == 0 {
+		return
+	}
+	segment, rest := segments[0], segments[1:]
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		if segment == "*" {
+			for key := range v {
+				if len(rest) == 0 {
+					delete(v, key)
+				} else {
+					removeJsonPath(v[key], rest)
+				}
+			}
+		} else if len(rest) == 0 {
+			delete(v, segment)
+		} else {
+			removeJsonPath(v[segment], rest)
+		}
+	case []interface{}:
+		// elements can't be removed from an array without shifting the others, so only descend into them
+		if len(rest) == 0 {
+			return
+		}
+		if segment == "*" {
+			for _, element := range v {
+				removeJsonPath(element, rest)
+			}
+		} else if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(v) {
+			removeJsonPath(v[i], rest)
+		}
+	}
+}
+
+// getJsonPath returns the value at the given path in a decoded JSON document. If the path contains a wildcard, the
+// values it matches are returned as an array.
+func getJsonPath(doc interface{}, segments []string) (interface{}, bool) {
+	if len(segments) == 0 {
+		return doc, true
+	}
+	segment, rest := segments[0], segments[1:]
+
+	if segment == "*" {
+		var children []interface{}
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				children = append(children, v[key])
+			}
+		case []interface{}:
+			children = v
+		default:
+			return nil, false
+		}
+		matches := make([]interface{}, 0)
+		for _, child := range children {
+			if match, found := getJsonPath(child, rest); found {
+				matches = append(matches, match)
+			}
+		}
+		return matches, true
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		if child, found := v[segment]; found {
+			return getJsonPath(child, rest)
+		}
+	case []interface{}:
+		if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(v) {
+			return getJsonPath(v[i], rest)
+		}
+	}
+	return nil, false
+}
+
+// jsonValueToString converts a decoded JSON value to a string. Strings are returned as they are, numbers and booleans
+// are formatted and objects and arrays are JSON encoded.
+func jsonValueToString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "", nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+// removeJsonPaths removes the default ignored fields and the given paths from a decoded JSON document.
+func removeJsonPaths(doc interface{}, paths []string) {
+	for _, path := range append(append([]string{}, defaultIgnoredFields...), paths...) {
+		if segments, err := parseJsonPath(path); err == nil {
+			removeJsonPath(doc, segments)
+		}
+	}
+}
+
+// normalizeJson decodes a JSON document and removes the ignored fields from it, so that two documents can be compared
+// regardless of key order, whitespace, numeric formatting or properties owned by the service.
+func normalizeJson(s string, ignoreFields []string) (interface{}, error) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(s), &doc); err != nil {
+		return nil, err
+	}
+	removeJsonPaths(doc, ignoreFields)
+	return doc, nil
+}
+
+func getIgnoreFields(d *schema.ResourceData) []string {
+	var ignoreFields []string
+	for _, f := range d.Get("ignore_fields").([]interface{}) {
+		if s, ok := f.(string); ok {
+			ignoreFields = append(ignoreFields, s)
+		}
+	}
+	return ignoreFields
+}
+
+// suppressEquivalentJsonDiffs suppresses the diff of a JSON attribute when both values are semantically the same
+// document once the ignore_fields of the resource have been removed from them.
+func suppressEquivalentJsonDiffs(_, old, new string, d *schema.ResourceData) bool {
+	ignoreFields := getIgnoreFields(d)
+
+	oldDoc, err := normalizeJson(old, ignoreFields)
+	if err != nil {
+		return false
+	}
+	newDoc, err := normalizeJson(new, ignoreFields)
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(oldDoc, newDoc)
+}
+
+// suppressEquivalentJsonValueDiffs suppresses the diff of a JSON attribute when both values are semantically the same,
+// without ignoring any of their properties.
+func suppressEquivalentJsonValueDiffs(_, old, new string, _ *schema.ResourceData) bool {
+	var oldValue, newValue interface{}
+	if err := json.Unmarshal([]byte(old), &oldValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
+// validateJsonObject validates that a string is a JSON object.
+func validateJsonObject(v interface{}, k string) ([]string, []error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(v.(string)), &value); err != nil {
+		return nil, []error{fmt.Errorf("%q contains invalid JSON: %s", k, err)}
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, []error{fmt.Errorf("%q must be a JSON object", k)}
+	}
+	return nil, nil
+}
@@ -0,0 +1,145 @@
+package mcma
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseJsonPath(t *testing.T) {
+	cases := map[string][]string{
+		"dateCreated":        {"dateCreated"},
+		"$.metadata.name":    {"metadata", "name"},
+		"locators.*.url":     {"locators", "*", "url"},
+		"$.essences.0.title": {"essences", "0", "title"},
+		"$.locators[1].url":  {"locators", "1", "url"},
+		"$.locators[*].url":  {"locators", "*", "url"},
+	}
+	for path, expected := range cases {
+		segments, err := parseJsonPath(path)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", path, err)
+			continue
+		}
+		if len(segments) != len(expected) {
+			t.Errorf("parsing %q: expected %v, got %v", path, expected, segments)
+			continue
+		}
+		for i := range segments {
+			if segments[i] != expected[i] {
+				t.Errorf("parsing %q: expected %v, got %v", path, expected, segments)
+				break
+			}
+		}
+	}
+
+	for _, path := range []string{"", "$", "$.", "metadata..name", "metadata."} {
+		if _, err := parseJsonPath(path); err == nil {
+			t.Errorf("expected error parsing %q", path)
+		}
+	}
+}
+
+func TestSuppressEquivalentJsonDiffs(t *testing.T) {
+	cases := []struct {
+		name         string
+		old          string
+		new          string
+		ignoreFields []interface{}
+		suppress     bool
+	}{
+		{
+			name:     "key order and whitespace",
+			old:      `{"metadata":{"name":"a","description":"b"},"status":"NEW"}`,
+			new:      "{\n  \"status\": \"NEW\",\n  \"metadata\": { \"description\": \"b\", \"name\": \"a\" }\n}",
+			suppress: true,
+		},
+		{
+			name:     "numeric formatting",
+			old:      `{"duration":1.0,"frames":1e2}`,
+			new:      `{"duration":1,"frames":100}`,
+			suppress: true,
+		},
+		{
+			name:     "default ignored fields",
+			old:      `{"status":"NEW"}`,
+			new:      `{"status":"NEW","dateCreated":"2022-01-01T00:00:00Z","dateModified":"2022-01-01T00:00:00Z"}`,
+			suppress: true,
+		},
+		{
+			name:         "ignored fields",
+			old:          `{"status":"NEW","metadata":{"name":"a"},"locators":[{"url":"s3://a"},{"url":"s3://b"}]}`,
+			new:          `{"status":"NEW","metadata":{"name":"a","checksum":"abc"},"locators":[{},{}]}`,
+			ignoreFields: []interface{}{"$.metadata.checksum", "locators.*.url"},
+			suppress:     true,
+		},
+		{
+			name:     "changed value",
+			old:      `{"status":"NEW"}`,
+			new:      `{"status":"COMPLETED"}`,
+			suppress: false,
+		},
+		{
+			name:     "added property",
+			old:      `{"status":"NEW"}`,
+			new:      `{"status":"NEW","metadata":{"checksum":"abc"}}`,
+			suppress: false,
+		},
+		{
+			name:     "array order",
+			old:      `{"tags":["a","b"]}`,
+			new:      `{"tags":["b","a"]}`,
+			suppress: false,
+		},
+		{
+			name:     "invalid json",
+			old:      `{"status":"NEW"}`,
+			new:      `{"status":`,
+			suppress: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceMcmaResource().Schema, map[string]interface{}{
+				"type":          "BMContent",
+				"resource_json": c.new,
+				"ignore_fields": c.ignoreFields,
+			})
+			if suppress := suppressEquivalentJsonDiffs("resource_json", c.old, c.new, d); suppress != c.suppress {
+				t.Errorf("expected suppress to be %t, got %t", c.suppress, suppress)
+			}
+		})
+	}
+}
+
+func TestSuppressEquivalentJsonValueDiffs(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		suppress bool
+	}{
+		{`{"tier":"premium","priority":2}`, "{\n  \"priority\": 2.0,\n  \"tier\": \"premium\"\n}", true},
+		{`{"dateCreated":"2022-01-01"}`, `{}`, false},
+		{`{"limits":{"maxDuration":3600}}`, `{"limits":{"maxDuration":3601}}`, false},
+		{`{"tier":"premium"}`, ``, false},
+	}
+	for _, c := range cases {
+		if suppress := suppressEquivalentJsonValueDiffs("custom_properties_json", c.old, c.new, nil); suppress != c.suppress {
+			t.Errorf("%s -> %s: expected suppress to be %t, got %t", c.old, c.new, c.suppress, suppress)
+		}
+	}
+}
+
+func TestValidateJsonObject(t *testing.T) {
+	for _, v := range []string{`{}`, `{"tier":"premium","limits":{"maxDuration":3600}}`} {
+		if _, errs := validateJsonObject(v, "custom_properties_json"); len(errs) != 0 {
+			t.Errorf("unexpected errors for %s: %v", v, errs)
+		}
+	}
+	for _, v := range []string{`[]`, `"premium"`, `{"tier":`} {
+		if _, errs := validateJsonObject(v, "custom_properties_json"); len(errs) == 0 {
+			t.Errorf("expected errors for %s", v)
+		}
+	}
+}
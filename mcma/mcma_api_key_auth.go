@@ -2,15 +2,43 @@ package mcma
 
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
-	mcmaclient "github.com/ebu/mcma-libraries-go/client"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
 )
 
+// mcmaApiKeyAuthEnvVars configures an mcma_api_key_auth block from MCMA_API_KEY when the block is not specified.
+var mcmaApiKeyAuthEnvVars = map[string]string{
+	"api_key": "MCMA_API_KEY",
+}
+
+func mcmaApiKeyAuthResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"auth_type": {
+				Type:        schema.TypeString,
+				Description: "The auth type under which the authenticator is registered. Services and resource endpoints with this auth type will use it. Use different auth types to configure several mcma_api_key_auth blocks with different API keys.",
+				Optional:    true,
+				Default:     "McmaApiKey",
+			},
+			"api_key": {
+				Type:        schema.TypeString,
+				Description: "The MCMA API key (header = 'x-mcma-api-key') to use for authentication. Set from the MCMA_API_KEY environment variable when no mcma_api_key_auth block is specified.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
 func GetMcmaApiKeyAuthenticator(authData map[string]interface{}) (mcmaclient.Authenticator, diag.Diagnostics) {
 	apiKey, d := GetAuthDataString(authData, "api_key", true)
 	if d != nil {
 		return nil, d
 	}
+	if apiKey == "" {
+		return nil, authDataError("api_key", "MCMA API key not specified.", "api_key not specified and MCMA_API_KEY environment variable not set")
+	}
 
 	return mcmaclient.NewMcmaApiKeyAuthenticator(apiKey), nil
 }
@@ -0,0 +1,199 @@
+package mcma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+)
+
+// maxTokenRefreshWindow is how long before expiry a cached access token is refreshed. Tokens with a short
+// lifetime are refreshed halfway through it instead.
+const maxTokenRefreshWindow = 60 * time.Second
+
+// oauth2ClientCredentialsAuthEnvVars are the MCMA_OAUTH2_* environment variables used in place of an absent
+// oauth2_client_credentials_auth block.
+var oauth2ClientCredentialsAuthEnvVars = map[string]string{
+	"token_url":     "MCMA_OAUTH2_TOKEN_URL",
+	"client_id":     "MCMA_OAUTH2_CLIENT_ID",
+	"client_secret": "MCMA_OAUTH2_CLIENT_SECRET",
+	"audience":      "MCMA_OAUTH2_AUDIENCE",
+}
+
+func oauth2ClientCredentialsAuthResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"auth_type": {
+				Type:        schema.TypeString,
+				Description: "The auth type under which the authenticator is registered. Services and resource endpoints with this auth type will use it. Use different auth types to configure several oauth2_client_credentials_auth blocks.",
+				Optional:    true,
+				Default:     "OAuth2",
+			},
+			"token_url": {
+				Type:        schema.TypeString,
+				Description: "The url of the token endpoint of the OAuth2 authorization server. Set from the MCMA_OAUTH2_TOKEN_URL environment variable when no oauth2_client_credentials_auth block is specified.",
+				Optional:    true,
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Description: "The client ID to use for the client credentials grant. Set from the MCMA_OAUTH2_CLIENT_ID environment variable when no oauth2_client_credentials_auth block is specified.",
+				Optional:    true,
+			},
+			"client_secret": {
+				Type:        schema.TypeString,
+				Description: "The client secret to use for the client credentials grant. Set from the MCMA_OAUTH2_CLIENT_SECRET environment variable when no oauth2_client_credentials_auth block is specified.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"scopes": {
+				Type:        schema.TypeList,
+				Description: "The scopes to request for the access token.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"audience": {
+				Type:        schema.TypeString,
+				Description: "The audience to request for the access token, for authorization servers that require it. Set from the MCMA_OAUTH2_AUDIENCE environment variable when no oauth2_client_credentials_auth block is specified.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+type oauth2ClientCredentialsAuthenticator struct {
+	tokenUrl     string
+	clientId     string
+	clientSecret string
+	scopes       []string
+	audience     string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mutex       sync.Mutex
+	accessToken string
+	refreshAt   time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (a *oauth2ClientCredentialsAuthenticator) Authenticate(request *http.Request) error {
+	accessToken, err := a.getAccessToken()
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	return nil
+}
+
+// getAccessToken returns the cached access token, fetching a new one if there is none yet or if the cached one
+// is about to expire. Tokens returned without an expires_in are kept for the lifetime of the provider.
+func (a *oauth2ClientCredentialsAuthenticator) getAccessToken() (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.accessToken != "" && (a.refreshAt.IsZero() || a.now().Before(a.refreshAt)) {
+		return a.accessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", a.clientId)
+	form.Set("client_secret", a.clientSecret)
+	if len(a.scopes) > 0 {
+		form.Set("scope", strings.Join(a.scopes, " "))
+	}
+	if a.audience != "" {
+		form.Set("audience", a.audience)
+	}
+
+	requestedAt := a.now()
+	resp, err := a.httpClient.PostForm(a.tokenUrl, form)
+	if err != nil {
+		return "", fmt.Errorf("error requesting access token from %s: %v", a.tokenUrl, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error requesting access token from %s: unexpected status %s", a.tokenUrl, resp.Status)
+	}
+
+	var tokenResponse oauth2TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("error parsing access token response from %s: %v", a.tokenUrl, err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", fmt.Errorf("access token response from %s did not contain an access token", a.tokenUrl)
+	}
+
+	a.accessToken = tokenResponse.AccessToken
+	a.refreshAt = time.Time{}
+	if tokenResponse.ExpiresIn > 0 {
+		lifetime := time.Duration(tokenResponse.ExpiresIn) * time.Second
+		refreshWindow := lifetime / 2
+		if refreshWindow > maxTokenRefreshWindow {
+			refreshWindow = maxTokenRefreshWindow
+		}
+		a.refreshAt = requestedAt.Add(lifetime - refreshWindow)
+	}
+
+	return a.accessToken, nil
+}
+
+func GetOAuth2ClientCredentialsAuthenticator(authData map[string]interface{}, httpClient *http.Client) (mcmaclient.Authenticator, diag.Diagnostics) {
+	tokenUrl, d := GetAuthDataString(authData, "token_url", true)
+	if d != nil {
+		return nil, d
+	}
+	if tokenUrl == "" {
+		return nil, authDataError("token_url", "OAuth2 token url not specified.", "token_url not specified and MCMA_OAUTH2_TOKEN_URL environment variable not set")
+	}
+	clientId, d := GetAuthDataString(authData, "client_id", true)
+	if d != nil {
+		return nil, d
+	}
+	if clientId == "" {
+		return nil, authDataError("client_id", "OAuth2 client ID not specified.", "client_id not specified and MCMA_OAUTH2_CLIENT_ID environment variable not set")
+	}
+	clientSecret, d := GetAuthDataString(authData, "client_secret", true)
+	if d != nil {
+		return nil, d
+	}
+	if clientSecret == "" {
+		return nil, authDataError("client_secret", "OAuth2 client secret not specified.", "client_secret not specified and MCMA_OAUTH2_CLIENT_SECRET environment variable not set")
+	}
+	audience, d := GetAuthDataString(authData, "audience", false)
+	if d != nil {
+		return nil, d
+	}
+
+	var scopes []string
+	if rawScopes, ok := authData["scopes"].([]interface{}); ok {
+		for _, scope := range rawScopes {
+			scopes = append(scopes, scope.(string))
+		}
+	}
+
+	return &oauth2ClientCredentialsAuthenticator{
+		tokenUrl:     tokenUrl,
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		audience:     audience,
+		httpClient:   httpClient,
+		now:          time.Now,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package mcma
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("error parsing token request: %s", err)
+		}
+		expectedForm := map[string]string{
+			"grant_type":    "client_credentials",
+			"client_id":     "test-client",
+			"client_secret": "test-secret",
+			"scope":         "mcma/read mcma/write",
+			"audience":      "https://mcma.example.com",
+		}
+		for k, v := range expectedForm {
+			if r.PostForm.Get(k) != v {
+				t.Errorf("expected token request parameter %s to be '%s', got '%s'", k, v, r.PostForm.Get(k))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, requestCount, expiresIn)
+	}))
+	return server, &requestCount
+}
+
+func TestOAuth2ClientCredentialsAuthenticator(t *testing.T) {
+	server, requestCount := newTestTokenServer(t, 3600)
+	defer server.Close()
+
+	authenticator, d := GetOAuth2ClientCredentialsAuthenticator(map[string]interface{}{
+		"token_url":     server.URL,
+		"client_id":     "test-client",
+		"client_secret": "test-secret",
+		"scopes":        []interface{}{"mcma/read", "mcma/write"},
+		"audience":      "https://mcma.example.com",
+	}, http.DefaultClient)
+	if d != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", d)
+	}
+
+	now := time.Now()
+	authenticator.(*oauth2ClientCredentialsAuthenticator).now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		request, _ := http.NewRequest(http.MethodGet, "https://service.example.com/api/jobs", nil)
+		if err := authenticator.Authenticate(request); err != nil {
+			t.Fatalf("unexpected error authenticating request: %s", err)
+		}
+		if header := request.Header.Get("Authorization"); header != "Bearer token-1" {
+			t.Fatalf("expected cached token in Authorization header, got '%s'", header)
+		}
+	}
+	if *requestCount != 1 {
+		t.Fatalf("expected 1 token request, got %d", *requestCount)
+	}
+
+	now = now.Add(3550 * time.Second)
+	request, _ := http.NewRequest(http.MethodGet, "https://service.example.com/api/jobs", nil)
+	if err := authenticator.Authenticate(request); err != nil {
+		t.Fatalf("unexpected error authenticating request: %s", err)
+	}
+	if header := request.Header.Get("Authorization"); header != "Bearer token-2" {
+		t.Fatalf("expected refreshed token in Authorization header, got '%s'", header)
+	}
+}
+
+func TestOAuth2ClientCredentialsAuthenticator_errorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	authenticator, d := GetOAuth2ClientCredentialsAuthenticator(map[string]interface{}{
+		"token_url":     server.URL,
+		"client_id":     "test-client",
+		"client_secret": "wrong-secret",
+	}, http.DefaultClient)
+	if d != nil {
+		t.Fatalf("unexpected error creating authenticator: %v", d)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "https://service.example.com/api/jobs", nil)
+	if err := authenticator.Authenticate(request); err == nil {
+		t.Fatal("expected error when token endpoint rejects the client")
+	}
+}
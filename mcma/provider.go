@@ -2,11 +2,19 @@ package mcma
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
-	mcmaclient "github.com/ebu/mcma-libraries-go/client"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
 )
 
 func init() {
@@ -18,66 +26,97 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"service_registry_url": {
 				Type:        schema.TypeString,
-				Description: "The url to the services endpoint of the MCMA Service Registry",
-				Required:    true,
+				Description: "The url to the services endpoint of the MCMA Service Registry. Can also be set with the MCMA_SERVICE_REGISTRY_URL environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MCMA_SERVICE_REGISTRY_URL", nil),
 			},
 			"service_registry_auth_type": {
 				Type:        schema.TypeString,
-				Description: "The auth type to use for the services endpoint of the MCMA Service Registry",
+				Description: "The auth type to use for the services endpoint of the MCMA Service Registry. Can also be set with the MCMA_SERVICE_REGISTRY_AUTH_TYPE environment variable.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MCMA_SERVICE_REGISTRY_AUTH_TYPE", nil),
+			},
+			"tls": {
+				Type:        schema.TypeList,
+				Description: "TLS settings for all HTTP calls made by the provider, including calls to the service registry, to the services it lists and to authentication endpoints. If no block is specified, the block is configured from the MCMA_TLS_* environment variables when any of them is set.",
+				Optional:    true,
+				MaxItems:    1,
+				Elem:        tlsResource(),
+			},
+			"retry": {
+				Type:        schema.TypeList,
+				Description: "The policy for retrying requests that fail with a transient error, such as throttling by an API gateway. If no block is specified, requests are retried up to 4 times on status codes 429, 502, 503 and 504.",
+				Optional:    true,
+				MaxItems:    1,
+				Elem:        retryResource(),
+			},
+			"conflict_detection": {
+				Type:        schema.TypeBool,
+				Description: "Flag indicating if the provider should check that a service, job profile or resource has not been modified in the service registry since it was last known to Terraform, as recorded in its known_date_modified attribute, before updating it. When the dateModified of the resource has changed, the update fails and reports the attributes that were changed remotely. Can also be set with the MCMA_CONFLICT_DETECTION environment variable.",
 				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MCMA_CONFLICT_DETECTION", false),
 			},
 			"aws4_auth": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"region": {
-							Type:        schema.TypeString,
-							Description: "The AWS region to use for authentication",
-							Optional:    true,
-						},
-						"profile": {
-							Type:        schema.TypeString,
-							Description: "The AWS profile to use for authentication",
-							Optional:    true,
-						},
-						"access_key": {
-							Type:        schema.TypeString,
-							Description: "The AWS access key to use for authentication. Requires that secret_key also be specified",
-							Optional:    true,
-						},
-						"secret_key": {
-							Type:        schema.TypeString,
-							Description: "The AWS secret key to use for authentication. Requires that access_key also be specified",
-							Optional:    true,
-						},
-					},
-				},
+				Type:        schema.TypeSet,
+				Description: "AWS4 authentication settings. Multiple blocks can be specified with different auth types. If no block is specified, a block is configured from the MCMA_AWS_* environment variables when any of them is set.",
+				Optional:    true,
+				Elem:        aws4AuthResource(),
 			},
 			"mcma_api_key_auth": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"api_key": {
-							Type:        schema.TypeString,
-							Description: "The MCMA API key (header = 'x-mcma-api-key') to use for authentication",
-							Required:    true,
-						},
-					},
-				},
+				Type:        schema.TypeSet,
+				Description: "MCMA API key authentication settings. Multiple blocks can be specified with different auth types. If no block is specified, a block is configured from the MCMA_API_KEY environment variable when it is set.",
+				Optional:    true,
+				Elem:        mcmaApiKeyAuthResource(),
+			},
+			"oauth2_client_credentials_auth": {
+				Type:        schema.TypeSet,
+				Description: "OAuth2 client credentials authentication settings. Access tokens are cached and refreshed before they expire. Multiple blocks can be specified with different auth types. If no block is specified, a block is configured from the MCMA_OAUTH2_* environment variables when any of them is set.",
+				Optional:    true,
+				Elem:        oauth2ClientCredentialsAuthResource(),
+			},
+			"bearer_token_auth": {
+				Type:        schema.TypeSet,
+				Description: "Bearer token (e.g. JWT) authentication settings. Multiple blocks can be specified with different auth types. If no block is specified, a block is configured from the MCMA_BEARER_TOKEN or MCMA_BEARER_TOKEN_FILE environment variable when either of them is set.",
+				Optional:    true,
+				Elem:        bearerTokenAuthResource(),
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"mcma_service":     resourceService(),
 			"mcma_job_profile": resourceJobProfile(),
 			"mcma_resource":    resourceMcmaResource(),
+			"mcma_job":         resourceJob(),
 		},
-		DataSourcesMap: map[string]*schema.Resource{},
-		ConfigureContextFunc: func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-			return configure(d)
+		DataSourcesMap: map[string]*schema.Resource{
+			"mcma_service":      dataSourceService(),
+			"mcma_services":     dataSourceServices(),
+			"mcma_job_profile":  dataSourceJobProfile(),
+			"mcma_job_profiles": dataSourceJobProfiles(),
+			"mcma_job_input":    dataSourceJobInput(),
+			"mcma_resource":     dataSourceMcmaResource(),
+			"mcma_resources":    dataSourceMcmaResources(),
 		},
+		ConfigureContextFunc: configure,
+	}
+}
+
+// getBlockDataFromEnvVars builds the data for a block that is absent from the provider configuration from the
+// environment variables mapped to its attributes. Returns nil if none of them is set. Blocks that are specified in the
+// configuration are never completed from the environment, so that explicit settings are not mixed with or overridden
+// by the environment of the process running Terraform.
+func getBlockDataFromEnvVars(envVars map[string]string) map[string]interface{} {
+	var blockData map[string]interface{}
+	for key, envVar := range envVars {
+		value := os.Getenv(envVar)
+		if value == "" {
+			continue
+		}
+		if blockData == nil {
+			blockData = make(map[string]interface{})
+		}
+		blockData[key] = value
 	}
+	return blockData
 }
 
 func addAuthToMap(
@@ -85,34 +124,75 @@ func addAuthToMap(
 	resourceData *schema.ResourceData,
 	authType string,
 	authKey string,
+	envVars map[string]string,
 	authFactory func(map[string]interface{}) (mcmaclient.Authenticator, diag.Diagnostics),
 ) diag.Diagnostics {
+	blockPath := cty.GetAttrPath(authKey + "_auth")
 	blocks := resourceData.Get(authKey + "_auth").(*schema.Set).List()
-	switch len(blocks) {
-	case 0:
-		return nil
-	case 1:
-		authenticator, d := authFactory(blocks[0].(map[string]interface{}))
+	if len(blocks) == 0 {
+		if authData := getBlockDataFromEnvVars(envVars); authData != nil {
+			blocks = append(blocks, authData)
+		}
+	}
+
+	var diags diag.Diagnostics
+	for _, block := range blocks {
+		authData := block.(map[string]interface{})
+		blockAuthType := authType
+		if configuredAuthType, ok := authData["auth_type"].(string); ok && configuredAuthType != "" {
+			blockAuthType = configuredAuthType
+		}
+		if _, exists := authMap[blockAuthType]; exists {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Auth type %s configured more than once.", blockAuthType),
+				Detail:        fmt.Sprintf("A %s_auth block uses auth type %s, which is already used by another auth block. Set a different auth_type on each auth block.", authKey, blockAuthType),
+				AttributePath: blockPath.GetAttr("auth_type"),
+			})
+			continue
+		}
+		authenticator, d := authFactory(authData)
 		if d != nil {
-			return d
+			diags = append(diags, withAttributePathPrefix(d, blockPath)...)
+			continue
 		}
-		authMap[authType] = authenticator
-		return nil
-	default:
-		return diag.Errorf("only 1 %s_auth block allowed", authType)
+		authMap[blockAuthType] = authenticator
 	}
+	return diags
 }
 
-func configure(d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+func configure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	serviceRegistryUrl := d.Get("service_registry_url").(string)
 	if serviceRegistryUrl == "" {
-		return nil, nil
+		return nil, diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Url for MCMA service registry not provided.",
+				Detail:        "The url for the MCMA service registry must be set with the service_registry_url attribute or the MCMA_SERVICE_REGISTRY_URL environment variable.",
+				AttributePath: cty.GetAttrPath("service_registry_url"),
+			},
+		}
 	}
 	serviceRegistryAuthType := d.Get("service_registry_auth_type").(string)
 
+	httpClient, di := newHttpClient(d)
+	if di != nil {
+		return nil, di
+	}
+
+	var diags diag.Diagnostics
 	authMap := make(map[string]mcmaclient.Authenticator)
-	addAuthToMap(authMap, d, "AWS4", "aws4", GetAWS4Authenticator)
-	addAuthToMap(authMap, d, "McmaApiKey", "mcma_api_key", GetMcmaApiKeyAuthenticator)
+	diags = append(diags, addAuthToMap(authMap, d, "AWS4", "aws4", aws4AuthEnvVars, func(authData map[string]interface{}) (mcmaclient.Authenticator, diag.Diagnostics) {
+		return GetAWS4Authenticator(authData, httpClient)
+	})...)
+	diags = append(diags, addAuthToMap(authMap, d, "McmaApiKey", "mcma_api_key", mcmaApiKeyAuthEnvVars, GetMcmaApiKeyAuthenticator)...)
+	diags = append(diags, addAuthToMap(authMap, d, "OAuth2", "oauth2_client_credentials", oauth2ClientCredentialsAuthEnvVars, func(authData map[string]interface{}) (mcmaclient.Authenticator, diag.Diagnostics) {
+		return GetOAuth2ClientCredentialsAuthenticator(authData, httpClient)
+	})...)
+	diags = append(diags, addAuthToMap(authMap, d, "JWT", "bearer_token", bearerTokenAuthEnvVars, GetBearerTokenAuthenticator)...)
+	if diags.HasError() {
+		return nil, diags
+	}
 
 	if len(authMap) == 1 && serviceRegistryAuthType == "" {
 		for s := range authMap {
@@ -120,6 +200,22 @@ func configure(d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		}
 	}
 
+	if _, found := authMap[serviceRegistryAuthType]; serviceRegistryAuthType != "" && !found {
+		var configuredAuthTypes []string
+		for s := range authMap {
+			configuredAuthTypes = append(configuredAuthTypes, s)
+		}
+		sort.Strings(configuredAuthTypes)
+		return nil, diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("No authenticator configured for auth type %s.", serviceRegistryAuthType),
+				Detail:        fmt.Sprintf("service_registry_auth_type must be one of the auth types configured with an auth block. Configured auth types: [%s].", strings.Join(configuredAuthTypes, ", ")),
+				AttributePath: cty.GetAttrPath("service_registry_auth_type"),
+			},
+		}
+	}
+
 	var resourceManager mcmaclient.ResourceManager
 	if len(serviceRegistryAuthType) != 0 {
 		resourceManager = mcmaclient.NewResourceManager(serviceRegistryUrl, serviceRegistryAuthType)
@@ -127,11 +223,30 @@ func configure(d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		resourceManager = mcmaclient.NewResourceManagerNoAuth(serviceRegistryUrl)
 	}
 
+	resourceManager.SetHttpClient(httpClient)
+
 	for key, a := range authMap {
 		resourceManager.AddAuth(key, a)
 	}
 
-	return &resourceManager, nil
+	return &providerMeta{
+		resourceManager:   &resourceManager,
+		httpClient:        httpClient,
+		authenticators:    authMap,
+		conflictDetection: d.Get("conflict_detection").(bool),
+	}, nil
+}
+
+// providerMeta holds the configured provider, as passed to the CRUD functions of the resources and data sources.
+type providerMeta struct {
+	resourceManager *mcmaclient.ResourceManager
+	// httpClient is the client with the TLS and retry settings of the provider configuration, for requests that the
+	// resource manager cannot send
+	httpClient *http.Client
+	// authenticators holds the authenticator for each configured auth type, for requests that the resource manager
+	// cannot send
+	authenticators    map[string]mcmaclient.Authenticator
+	conflictDetection bool
 }
 
 func getResourceManager(m interface{}) (*mcmaclient.ResourceManager, diag.Diagnostics) {
@@ -144,5 +259,50 @@ func getResourceManager(m interface{}) (*mcmaclient.ResourceManager, diag.Diagno
 			},
 		}
 	}
-	return m.(*mcmaclient.ResourceManager), nil
+	return m.(*providerMeta).resourceManager, nil
+}
+
+// getResourceByName queries the service registry for the objects of type T with the given name, using nameAndId to
+// read their name and id. Returns nil if there is no such object, and an error if there is more than one.
+func getResourceByName[T any](ctx context.Context, resourceManager *mcmaclient.ResourceManager, name string, description string, nameAndId func(T) (string, string)) (*T, diag.Diagnostics) {
+	var zero T
+	results, err := resourceManager.Query(ctx, reflect.TypeOf(zero), map[string]string{"name": name})
+	if err != nil {
+		return nil, diag.Errorf("error querying %s with name %s: %s", description, name, err)
+	}
+
+	var matches []T
+	var ids []string
+	for _, result := range results {
+		r := result.(T)
+		if resourceName, id := nameAndId(r); resourceName == name {
+			matches = append(matches, r)
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, diag.Errorf("found %d %s with name %s, use id to select one of %v", len(matches), description, name, ids)
+	}
+}
+
+func getServiceByName(ctx context.Context, resourceManager *mcmaclient.ResourceManager, name string) (*mcmamodel.Service, diag.Diagnostics) {
+	return getResourceByName(ctx, resourceManager, name, "services", func(service mcmamodel.Service) (string, string) {
+		return service.Name, service.Id
+	})
+}
+
+func getJobProfileByName(ctx context.Context, resourceManager *mcmaclient.ResourceManager, name string) (*mcmamodel.JobProfile, diag.Diagnostics) {
+	return getResourceByName(ctx, resourceManager, name, "job profiles", func(jobProfile mcmamodel.JobProfile) (string, string) {
+		return jobProfile.Name, jobProfile.Id
+	})
+}
+
+func getConflictDetection(m interface{}) bool {
+	return m != nil && m.(*providerMeta).conflictDetection
 }
@@ -1,9 +1,16 @@
 package mcma
 
 import (
+	"context"
+	"net/http"
 	"os"
+	"strings"
+	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/mcmatest"
 )
 
 var testAccProvider *schema.Provider
@@ -16,84 +23,209 @@ func init() {
 	}
 }
 
-type authBlock interface {
-	GetText() string
+func getAwsProfileProviderConfig(serviceRegistryUrl string, region string, profile string) string {
+	return mcmatest.ProviderConfig(serviceRegistryUrl, "", mcmatest.AWS4Auth{Region: region, Profile: profile})
 }
 
-type aws4AuthBlock struct {
-	region    string
-	profile   string
-	accessKey string
-	secretKey string
+// getAwsProfileProviderConfigFromEnvVars returns the configuration for the registry set in MCMA_AWS_SERVICE_REGISTRY_URL,
+// or for a fake registry that checks AWS4 signatures if it is not set.
+func getAwsProfileProviderConfigFromEnvVars() string {
+	serviceRegistryUrl := os.Getenv("MCMA_AWS_SERVICE_REGISTRY_URL")
+	if serviceRegistryUrl == "" {
+		return getFakeRegistry(mcmatest.AuthTypeAWS4).ProviderConfig()
+	}
+	return getAwsProfileProviderConfig(serviceRegistryUrl, os.Getenv("MCMA_AWS_REGION"), os.Getenv("MCMA_AWS_PROFILE"))
 }
 
-func (authBlock aws4AuthBlock) GetText() string {
-	authBlockText := "  aws4_auth {\n"
-	if authBlock.region != "" {
-		authBlockText += "    region = \"" + authBlock.region + "\"\n"
+func getMcmaApiKeyProviderConfig(serviceRegistryUrl, apiKey string) string {
+	return mcmatest.ProviderConfig(serviceRegistryUrl, "", mcmatest.McmaApiKeyAuth{ApiKey: apiKey})
+}
+
+// getMcmaApiKeyProviderConfigFromEnvVars returns the configuration for the registry set in
+// MCMA_API_KEY_SERVICE_REGISTRY_URL, or for a fake registry that checks the x-mcma-api-key header if it is not set.
+func getMcmaApiKeyProviderConfigFromEnvVars() string {
+	serviceRegistryUrl := os.Getenv("MCMA_API_KEY_SERVICE_REGISTRY_URL")
+	if serviceRegistryUrl == "" {
+		return getFakeRegistry(mcmatest.AuthTypeMcmaApiKey).ProviderConfig()
 	}
-	if authBlock.profile != "" {
-		authBlockText += "    profile = \"" + authBlock.profile + "\"\n"
+	return getMcmaApiKeyProviderConfig(serviceRegistryUrl, os.Getenv("MCMA_API_KEY"))
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
 	}
-	if authBlock.accessKey != "" {
-		authBlockText += "    access_key = \"" + authBlock.accessKey + "\"\n"
+}
+
+func TestProvider_serviceRegistryUrlFromEnvVar(t *testing.T) {
+	t.Setenv("MCMA_SERVICE_REGISTRY_URL", "https://service-registry-example.mcma.io/api/")
+	t.Setenv("MCMA_API_KEY", "abcd1234efgh5678")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+	if url := d.Get("service_registry_url").(string); url != "https://service-registry-example.mcma.io/api/" {
+		t.Fatalf("expected service_registry_url from environment variable, got '%s'", url)
 	}
-	if authBlock.secretKey != "" {
-		authBlockText += "    secret_key = \"" + authBlock.secretKey + "\"\n"
+	if _, di := configure(context.Background(), d); di.HasError() {
+		t.Fatalf("unexpected error configuring provider: %v", di)
 	}
-	authBlockText += "  }\n"
-	return authBlockText
 }
 
-type mcmaApiKeyAuthBlock struct {
-	apiKey string
-}
+func TestProvider_httpClient(t *testing.T) {
+	t.Setenv("MCMA_API_KEY", "abcd1234efgh5678")
+	defaultTransport := http.DefaultTransport
 
-func (authBlock mcmaApiKeyAuthBlock) GetText() string {
-	authBlockText := "  mcma_api_key_auth {\n"
-	if authBlock.apiKey != "" {
-		authBlockText += "    api_key = \"" + authBlock.apiKey + "\"\n"
+	configs := []map[string]interface{}{
+		{"service_registry_url": "https://registry-1.mcma.io/api/", "tls": []interface{}{map[string]interface{}{"insecure_skip_verify": true}}},
+		{"service_registry_url": "https://registry-2.mcma.io/api/"},
+	}
+	var httpClients []*http.Client
+	for _, config := range configs {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, config)
+		meta, di := configure(context.Background(), d)
+		if di.HasError() {
+			t.Fatalf("unexpected error configuring provider: %v", di)
+		}
+		httpClients = append(httpClients, meta.(*providerMeta).httpClient)
 	}
-	authBlockText += "  }\n"
-	return authBlockText
-}
 
-func getProviderConfig(serviceRegistryUrl string, serviceRegistryAuthType string, authBlocks []authBlock) string {
-	providerConfig := "provider \"mcma\" {\n"
-	providerConfig += "  service_registry_url = \"" + serviceRegistryUrl + "\"\n"
-	if serviceRegistryAuthType != "" {
-		serviceRegistryAuthType += "  service_registry_auth_type = \"" + serviceRegistryAuthType + "\"\n"
+	if http.DefaultTransport != defaultTransport {
+		t.Error("expected configuring the provider to leave the default HTTP transport untouched")
 	}
-	if authBlocks != nil && len(authBlocks) > 0 {
-		for _, authBlock := range authBlocks {
-			providerConfig += authBlock.GetText()
+	for i, httpClient := range httpClients {
+		transport, ok := httpClient.Transport.(*retryTransport)
+		if !ok {
+			t.Fatalf("expected HTTP client of configuration %d to retry requests, got %T", i, httpClient.Transport)
+		}
+		insecure := transport.next.(*http.Transport).TLSClientConfig.InsecureSkipVerify
+		if insecure != (i == 0) {
+			t.Errorf("expected HTTP client of configuration %d to use its own TLS settings, got insecure_skip_verify %t", i, insecure)
 		}
 	}
-	providerConfig += "}\n"
-	return providerConfig
 }
 
-func getAwsProfileProviderConfig(serviceRegistryUrl string, region string, profile string) string {
-	authBlocks := make([]authBlock, 1)
-	authBlocks[0] = aws4AuthBlock{
-		region:  region,
-		profile: profile,
+func TestProvider_serviceRegistryUrlNotSet(t *testing.T) {
+	t.Setenv("MCMA_SERVICE_REGISTRY_URL", "")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+	_, di := configure(context.Background(), d)
+	if !di.HasError() {
+		t.Fatal("expected error when service registry url is not set")
+	}
+	if !strings.Contains(di[0].Detail, "service_registry_url") || !strings.Contains(di[0].Detail, "MCMA_SERVICE_REGISTRY_URL") {
+		t.Fatalf("expected error to name attribute and environment variable, got '%s'", di[0].Detail)
 	}
-	return getProviderConfig(serviceRegistryUrl, "", authBlocks)
 }
 
-func getAwsProfileProviderConfigFromEnvVars() string {
-	return getAwsProfileProviderConfig(os.Getenv("MCMA_AWS_SERVICE_REGISTRY_URL"), os.Getenv("MCMA_AWS_REGION"), os.Getenv("MCMA_AWS_PROFILE"))
+func TestProvider_authConfigurationErrors(t *testing.T) {
+	cases := map[string]struct {
+		config        map[string]interface{}
+		attributePath cty.Path
+	}{
+		"access key without secret key": {
+			config: map[string]interface{}{
+				"aws4_auth": []interface{}{
+					map[string]interface{}{"region": "us-east-1", "access_key": "accesskey"},
+				},
+			},
+			attributePath: cty.GetAttrPath("aws4_auth").GetAttr("secret_key"),
+		},
+		"access key and profile": {
+			config: map[string]interface{}{
+				"aws4_auth": []interface{}{
+					map[string]interface{}{"region": "us-east-1", "access_key": "accesskey", "secret_key": "secretkey", "profile": "myprofile"},
+				},
+			},
+			attributePath: cty.GetAttrPath("aws4_auth").GetAttr("profile"),
+		},
+		"multiple api key blocks with the same auth type": {
+			config: map[string]interface{}{
+				"mcma_api_key_auth": []interface{}{
+					map[string]interface{}{"api_key": "key1"},
+					map[string]interface{}{"api_key": "key2"},
+				},
+			},
+			attributePath: cty.GetAttrPath("mcma_api_key_auth").GetAttr("auth_type"),
+		},
+		"duplicate auth type": {
+			config: map[string]interface{}{
+				"mcma_api_key_auth": []interface{}{
+					map[string]interface{}{"api_key": "key1"},
+				},
+				"bearer_token_auth": []interface{}{
+					map[string]interface{}{"auth_type": "McmaApiKey", "token": "token"},
+				},
+			},
+			attributePath: cty.GetAttrPath("bearer_token_auth").GetAttr("auth_type"),
+		},
+		"unknown service registry auth type": {
+			config: map[string]interface{}{
+				"service_registry_auth_type": "AWS4",
+				"mcma_api_key_auth": []interface{}{
+					map[string]interface{}{"api_key": "key1"},
+				},
+			},
+			attributePath: cty.GetAttrPath("service_registry_auth_type"),
+		},
+	}
+
+	for _, envVar := range []string{
+		"MCMA_AWS_REGION", "MCMA_AWS_PROFILE", "MCMA_AWS_ACCESS_KEY", "MCMA_AWS_SECRET_KEY", "MCMA_AWS_SESSION_TOKEN",
+		"MCMA_API_KEY", "MCMA_BEARER_TOKEN", "MCMA_BEARER_TOKEN_FILE",
+		"MCMA_OAUTH2_TOKEN_URL", "MCMA_OAUTH2_CLIENT_ID", "MCMA_OAUTH2_CLIENT_SECRET", "MCMA_OAUTH2_AUDIENCE",
+	} {
+		t.Setenv(envVar, "")
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			c.config["service_registry_url"] = "https://service-registry-example.mcma.io/api/"
+			d := schema.TestResourceDataRaw(t, Provider().Schema, c.config)
+			_, di := configure(context.Background(), d)
+			if !di.HasError() {
+				t.Fatal("expected error configuring provider")
+			}
+			if !di[0].AttributePath.Equals(c.attributePath) {
+				t.Fatalf("expected error for attribute path %#v, got %#v (%s)", c.attributePath, di[0].AttributePath, di[0].Detail)
+			}
+		})
+	}
 }
 
-func getMcmaApiKeyProviderConfig(serviceRegistryUrl, apiKey string) string {
-	authBlocks := make([]authBlock, 1)
-	authBlocks[0] = mcmaApiKeyAuthBlock{
-		apiKey: apiKey,
+func TestProvider_authBlockNotCompletedFromEnvVars(t *testing.T) {
+	t.Setenv("MCMA_AWS_PROFILE", "myprofile")
+	t.Setenv("MCMA_AWS_REGION", "eu-west-1")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"service_registry_url": "https://service-registry-example.mcma.io/api/",
+		"aws4_auth": []interface{}{
+			map[string]interface{}{"region": "us-east-1", "access_key": "accesskey", "secret_key": "secretkey"},
+		},
+	})
+	if _, di := configure(context.Background(), d); di.HasError() {
+		t.Fatalf("expected environment variables to be ignored for a configured aws4_auth block, got %v", di)
+	}
+
+	if authData := getBlockDataFromEnvVars(aws4AuthEnvVars); authData["profile"] != "myprofile" || authData["region"] != "eu-west-1" {
+		t.Fatalf("expected aws4_auth block to be built from environment variables when absent, got %v", authData)
 	}
-	return getProviderConfig(serviceRegistryUrl, "", authBlocks)
 }
 
-func getMcmaApiKeyProviderConfigFromEnvVars() string {
-	return getMcmaApiKeyProviderConfig(os.Getenv("MCMA_API_KEY_SERVICE_REGISTRY_URL"), os.Getenv("MCMA_API_KEY"))
+func TestProvider_multipleAuthenticatorsOfSameType(t *testing.T) {
+	t.Setenv("MCMA_AWS_PROFILE", "")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"service_registry_url":       "https://service-registry-example.mcma.io/api/",
+		"service_registry_auth_type": "AWS4-Account1",
+		"aws4_auth": []interface{}{
+			map[string]interface{}{"auth_type": "AWS4-Account1", "region": "us-east-1", "access_key": "accesskey1", "secret_key": "secretkey1"},
+			map[string]interface{}{"auth_type": "AWS4-Account2", "region": "eu-west-1", "access_key": "accesskey2", "secret_key": "secretkey2"},
+		},
+		"mcma_api_key_auth": []interface{}{
+			map[string]interface{}{"api_key": "key1"},
+			map[string]interface{}{"auth_type": "McmaApiKey-Partner", "api_key": "key2"},
+		},
+	})
+	if _, di := configure(context.Background(), d); di.HasError() {
+		t.Fatalf("unexpected error configuring provider: %v", di)
+	}
 }
@@ -0,0 +1,450 @@
+package mcma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	jobStatusCompleted = "Completed"
+	jobStatusFailed    = "Failed"
+	jobStatusCanceled  = "Canceled"
+
+	defaultJobCreateTimeout = 30 * time.Minute
+)
+
+func resourceJob() *schema.Resource {
+	return &schema.Resource{
+		Description: "A job submitted to an MCMA service, e.g. to ingest reference assets or warm up AI models while setting up an environment. The job is submitted when the resource is created and the provider waits until it has completed, failed or been canceled. A job that fails or is canceled is reported as an error, and is submitted again on the next apply. Jobs cannot be changed or deleted: changing any of their arguments submits a new job, and destroying the resource only removes it from state, unless cancel_on_destroy is set and it is still running.",
+
+		CreateContext: resourceJobCreate,
+		ReadContext:   resourceJobRead,
+		UpdateContext: resourceJobUpdate,
+		DeleteContext: resourceJobDelete,
+
+		CustomizeDiff: resourceJobCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultJobCreateTimeout),
+			Read:   schema.DefaultTimeout(defaultReadTimeout),
+			Delete: schema.DefaultTimeout(defaultDeleteTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Description: "The ID of the job. MCMA IDs are always absolute urls.",
+				Computed:    true,
+			},
+			"date_created": {
+				Type:        schema.TypeString,
+				Description: "The date and time at which the job was created.",
+				Computed:    true,
+			},
+			"date_modified": {
+				Type:        schema.TypeString,
+				Description: "The date and time at which the job was last modified.",
+				Computed:    true,
+			},
+			"job_type": {
+				Type:        schema.TypeString,
+				Description: "The MCMA type of the job, e.g. AmeJob. The job is submitted to the resource endpoint registered for this type.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"job_profile_id": {
+				Type:         schema.TypeString,
+				Description:  "The ID of the job profile of the job. Either job_profile_id or job_profile_name must be set.",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"job_profile_id", "job_profile_name"},
+			},
+			"job_profile_name": {
+				Type:        schema.TypeString,
+				Description: "The name of the job profile of the job, which is resolved to its ID through the service registry.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"job_input": {
+				Type:             schema.TypeString,
+				Description:      "The JSON object with the input parameters of the job. Differences in key order, whitespace and numeric formatting do not produce a plan.",
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateJsonObject,
+				DiffSuppressFunc: suppressEquivalentJsonValueDiffs,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Description: "Arbitrary values that, when changed, submit the job again.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"validate_job_input": {
+				Type:        schema.TypeBool,
+				Description: "Flag indicating if job_input should be validated against the input parameters of the job profile when planning to submit the job. Validation is skipped if the job profile is not known yet, e.g. when it is created in the same apply.",
+				Optional:    true,
+				Default:     true,
+			},
+			"cancel_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "Flag indicating if the job should be canceled when the resource is destroyed while it is still running, e.g. after its creation timed out.",
+				Optional:    true,
+				Default:     false,
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "The status of the job, e.g. Completed.",
+				Computed:    true,
+			},
+			"progress": {
+				Type:        schema.TypeFloat,
+				Description: "The progress of the job, as reported by the service that runs it.",
+				Computed:    true,
+			},
+			"job_output": {
+				Type:        schema.TypeString,
+				Description: "The JSON object with the output parameters of the job.",
+				Computed:    true,
+			},
+			"error": {
+				Type:        schema.TypeString,
+				Description: "The JSON of the problem details reported by the service if the job failed.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func isJobFinished(status string) bool {
+	return strings.EqualFold(status, jobStatusCompleted) ||
+		strings.EqualFold(status, jobStatusFailed) ||
+		strings.EqualFold(status, jobStatusCanceled)
+}
+
+// resourceJobCustomizeDiff validates the input of a job that is about to be submitted against its job profile, so that
+// mistakes in the input parameters are reported when planning instead of by the service that runs the job.
+func resourceJobCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if m == nil || !d.Get("validate_job_input").(bool) {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChanges("job_type", "job_profile_id", "job_profile_name", "job_input", "triggers") {
+		return nil
+	}
+	if !d.NewValueKnown("job_input") || !d.NewValueKnown("job_profile_id") || !d.NewValueKnown("job_profile_name") {
+		return nil
+	}
+
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return diagsToError(di)
+	}
+
+	jobProfileId := d.Get("job_profile_id").(string)
+	if name := d.Get("job_profile_name").(string); name != "" {
+		found, di := getJobProfileByName(ctx, resourceManager, name)
+		if di != nil {
+			return diagsToError(di)
+		}
+		if found == nil {
+			return nil
+		}
+		jobProfileId = found.Id
+	}
+	if jobProfileId == "" {
+		return nil
+	}
+
+	jobProfile, err := getJobProfileDocument(ctx, resourceManager, jobProfileId)
+	if err != nil || jobProfile == nil {
+		return err
+	}
+
+	jobInput, err := decodeJobInput(d.Get("job_input").(string))
+	if err != nil {
+		return fmt.Errorf("error parsing job_input: %s", err)
+	}
+
+	if validation := validateJobInput(*jobProfile, jobInput, false); !validation.valid() {
+		return fmt.Errorf("job_input is not valid for job profile %s:\n  - %s", jobProfile.Name, strings.Join(validation.Errors, "\n  - "))
+	}
+
+	return nil
+}
+
+func getJobFromResourceData(d *schema.ResourceData) (map[string]interface{}, error) {
+	jobInput, err := decodeJobInput(d.Get("job_input").(string))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing job_input: %s", err)
+	}
+	jobInput["@type"] = "JobParameterBag"
+
+	return map[string]interface{}{
+		"@type":        d.Get("job_type").(string),
+		"jobProfileId": d.Get("job_profile_id").(string),
+		"jobInput":     jobInput,
+	}, nil
+}
+
+// getJobParameterBagJson returns the JSON of a job parameter bag without its @type, or an empty string if it is not set.
+func getJobParameterBagJson(value interface{}) (string, error) {
+	parameterBag, ok := value.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	parameters := make(map[string]interface{}, len(parameterBag))
+	for k, v := range parameterBag {
+		if k != "@type" {
+			parameters[k] = v
+		}
+	}
+	jsonBytes, err := json.Marshal(parameters)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+func setJobResourceData(d *schema.ResourceData, job map[string]interface{}) diag.Diagnostics {
+	jobId, _ := job["id"].(string)
+	d.SetId(jobId)
+
+	jobType, _ := job["@type"].(string)
+	_ = d.Set("job_type", jobType)
+	jobProfileId, _ := job["jobProfileId"].(string)
+	_ = d.Set("job_profile_id", jobProfileId)
+	dateCreated, _ := job["dateCreated"].(string)
+	_ = d.Set("date_created", dateCreated)
+	dateModified, _ := job["dateModified"].(string)
+	_ = d.Set("date_modified", dateModified)
+	status, _ := job["status"].(string)
+	_ = d.Set("status", status)
+	progress, _ := job["progress"].(float64)
+	_ = d.Set("progress", progress)
+
+	// jobs cannot be changed, so the input is only read back when it is not known yet, i.e. when the job is imported,
+	// in case the service stores it differently
+	if d.Get("job_input").(string) == "" {
+		jobInput, err := getJobParameterBagJson(job["jobInput"])
+		if err != nil {
+			return diag.Errorf("error parsing job input of job with id %s: %s", jobId, err)
+		}
+		_ = d.Set("job_input", jobInput)
+	}
+
+	jobOutput, err := getJobParameterBagJson(job["jobOutput"])
+	if err != nil {
+		return diag.Errorf("error parsing job output of job with id %s: %s", jobId, err)
+	}
+	_ = d.Set("job_output", jobOutput)
+
+	jobError := ""
+	if job["error"] != nil {
+		jsonBytes, err := json.Marshal(job["error"])
+		if err != nil {
+			return diag.Errorf("error parsing error of job with id %s: %s", jobId, err)
+		}
+		jobError = string(jsonBytes)
+	}
+	_ = d.Set("error", jobError)
+
+	return diag.Diagnostics{}
+}
+
+func resourceJobRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	jobType := d.Get("job_type").(string)
+	jobId := d.Id()
+	get := getMcmaResourceVersion(ctx, resourceManager, jobType, jobId)
+	resource, _, err := get()
+	if err == nil && resource == nil && d.IsNewResource() {
+		// a job that was just submitted may not be returned by the service straight away
+		resource, err = readAfterWrite(ctx, "job "+jobId, time.Time{}, get)
+	}
+	if err != nil {
+		return diag.Errorf("error getting job of type %s with id %s: %s", jobType, jobId, err)
+	}
+	if resource == nil {
+		d.SetId("")
+		return diag.Diagnostics{}
+	}
+
+	return setJobResourceData(d, resource.(map[string]interface{}))
+}
+
+// waitForJob polls the job until it has finished, or until the context is done.
+func waitForJob(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	jobType := d.Get("job_type").(string)
+	jobId := d.Id()
+	var job map[string]interface{}
+	err := waitFor(ctx, "job "+jobId+" to finish", func() (bool, error) {
+		var err error
+		job, err = resourceManager.GetResource(ctx, jobType, jobId)
+		if err != nil || job == nil {
+			// a job that was just submitted may not be returned by the service straight away
+			return false, err
+		}
+		status, _ := job["status"].(string)
+		tflog.Debug(ctx, fmt.Sprintf("Job %s has status %s and progress %v", jobId, status, job["progress"]))
+		return isJobFinished(status), nil
+	})
+	if job != nil {
+		if di := setJobResourceData(d, job); di.HasError() {
+			return di
+		}
+	}
+	if err != nil {
+		return diag.Errorf("error waiting for job of type %s with id %s: %s", jobType, jobId, err)
+	}
+
+	if status := d.Get("status").(string); !strings.EqualFold(status, jobStatusCompleted) {
+		return diag.Diagnostics{
+			diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Job %s finished with status %s.", jobId, status),
+				Detail:   fmt.Sprintf("The job of type %s did not complete. Error reported by the service: %s", jobType, d.Get("error").(string)),
+			},
+		}
+	}
+
+	return diag.Diagnostics{}
+}
+
+func resourceJobCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	if name := d.Get("job_profile_name").(string); name != "" {
+		jobProfile, di := getJobProfileByName(ctx, resourceManager, name)
+		if di != nil {
+			return di
+		}
+		if jobProfile == nil {
+			return diag.Errorf("job profile with name %s not found", name)
+		}
+		_ = d.Set("job_profile_id", jobProfile.Id)
+	}
+
+	job, err := getJobFromResourceData(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	createdResource, err := resourceManager.Create(ctx, job)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	createdResourceMap := createdResource.(map[string]interface{})
+
+	d.SetId(createdResourceMap["id"].(string))
+
+	return waitForJob(ctx, d, m)
+}
+
+func resourceJobUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// only cancel_on_destroy can be changed, which is not sent to the service
+	return resourceJobRead(ctx, d, m)
+}
+
+func resourceJobDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.Get("cancel_on_destroy").(bool) {
+		return diag.Diagnostics{}
+	}
+
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	jobType := d.Get("job_type").(string)
+	jobId := d.Id()
+	job, err := resourceManager.GetResource(ctx, jobType, jobId)
+	if err != nil {
+		return diag.Errorf("error getting job of type %s with id %s: %s", jobType, jobId, err)
+	}
+	if job == nil {
+		return diag.Diagnostics{}
+	}
+	if status, _ := job["status"].(string); isJobFinished(status) {
+		return diag.Diagnostics{}
+	}
+
+	if err = cancelJob(ctx, m, jobId); err != nil {
+		return diag.Errorf("error canceling job of type %s with id %s: %s", jobType, jobId, err)
+	}
+
+	err = waitFor(ctx, "job "+jobId+" to be canceled", func() (bool, error) {
+		job, err := resourceManager.GetResource(ctx, jobType, jobId)
+		if err != nil || job == nil {
+			return job == nil, err
+		}
+		status, _ := job["status"].(string)
+		return isJobFinished(status), nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.Diagnostics{}
+}
+
+// cancelJob asks the service that runs a job to cancel it. The resource manager has no function for this, so the
+// request is sent with the HTTP client of the provider, for its TLS and retry settings, and the authenticator for the
+// auth type of the resource endpoint that the job was submitted to.
+func cancelJob(ctx context.Context, m interface{}, jobId string) error {
+	meta := m.(*providerMeta)
+	resourceEndpoint, authType, err := findResourceEndpointForId(ctx, meta.resourceManager, jobId)
+	if err != nil {
+		return err
+	}
+	if resourceEndpoint == nil {
+		return fmt.Errorf("no resource endpoint registered for job %s", jobId)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(jobId, "/")+"/cancel", nil)
+	if err != nil {
+		return err
+	}
+	if authType != "" {
+		authenticator, found := meta.authenticators[authType]
+		if !found {
+			return fmt.Errorf("no authenticator configured for auth type %s", authType)
+		}
+		if err = authenticator.Authenticate(request); err != nil {
+			return err
+		}
+	}
+
+	response, err := meta.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("service responded with status %s", response.Status)
+	}
+
+	return nil
+}
@@ -2,13 +2,15 @@ package mcma
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
-	mcmamodel "github.com/ebu/mcma-libraries-go/model"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
 )
 
 func resourceJobProfile() *schema.Resource {
@@ -20,6 +22,28 @@ func resourceJobProfile() *schema.Resource {
 		UpdateContext: resourceJobProfileUpdate,
 		DeleteContext: resourceJobProfileDelete,
 
+		Timeouts: defaultResourceTimeouts(),
+
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceJobProfileV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceJobProfileStateUpgradeV0,
+			},
+			{
+				Version: 1,
+				Type:    resourceJobProfileV1().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceJobProfileStateUpgradeV1,
+			},
+		},
+
+		CustomizeDiff: resourceJobProfileCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceJobProfileImport,
+		},
+
 		Schema: map[string]*schema.Schema{
 			"type": {
 				Type:        schema.TypeString,
@@ -42,58 +66,93 @@ func resourceJobProfile() *schema.Resource {
 				Description: "The date and time at which the job profile data was last modified.",
 				Computed:    true,
 			},
+			"known_date_modified": knownDateModifiedSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Description: "The name of the job profile.",
 				Required:    true,
 			},
 			"input_parameter": {
-				Type:        schema.TypeSet,
-				Description: "A list of input parameters (name and type) that must be provided when running a job for this profile.",
-				Optional:    true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:        schema.TypeString,
-							Description: "The name of the input parameter.",
-							Required:    true,
-						},
-						"type": {
-							Type:        schema.TypeString,
-							Description: "The type of the input parameter. Should specify an MCMA resource or primitive type.",
-							Required:    true,
-						},
-						"optional": {
-							Type:        schema.TypeBool,
-							Description: "Flag indicating if this input parameter must be provided or not",
-							Optional:    true,
-							Default:     false,
-						},
-					},
-				},
+				Type:          schema.TypeSet,
+				Description:   "A list of input parameters (name and type) that must be provided when running a job for this profile.",
+				Optional:      true,
+				ConflictsWith: []string{"ordered_input_parameter"},
+				Elem:          jobInputParameterResource(),
+			},
+			"ordered_input_parameter": {
+				Type:          schema.TypeList,
+				Description:   "The input parameters of the job profile, like input_parameter, but stored in the order in which they are declared, so that a change of the order in the service registry is reported as drift. Required input parameters must be declared before optional ones, as the job profile stores them in separate lists.",
+				Optional:      true,
+				ConflictsWith: []string{"input_parameter"},
+				Elem:          jobInputParameterResource(),
 			},
 			"output_parameter": {
-				Type:        schema.TypeSet,
-				Description: "A list of output parameters (name and type) that will be set on the job when the service has finished.",
-				Optional:    true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:        schema.TypeString,
-							Description: "The name of the output parameter.",
-							Required:    true,
-						},
-						"type": {
-							Type:        schema.TypeString,
-							Description: "The type of the output parameter. Should specify an MCMA resource or primitive type.",
-							Required:    true,
-						},
-					},
-				},
+				Type:          schema.TypeSet,
+				Description:   "A list of output parameters (name and type) that will be set on the job when the service has finished.",
+				Optional:      true,
+				ConflictsWith: []string{"ordered_output_parameter"},
+				Elem:          jobOutputParameterResource(),
+			},
+			"ordered_output_parameter": {
+				Type:          schema.TypeList,
+				Description:   "The output parameters of the job profile, like output_parameter, but stored in the order in which they are declared, so that a change of the order in the service registry is reported as drift.",
+				Optional:      true,
+				ConflictsWith: []string{"output_parameter"},
+				Elem:          jobOutputParameterResource(),
 			},
 			"custom_properties": {
-				Type:        schema.TypeMap,
-				Description: "A collection of key-value pairs specifying additional properties for the job profile.",
+				Type:          schema.TypeMap,
+				Description:   "A collection of key-value pairs specifying additional properties for the job profile. Values that are not strings are read back JSON encoded, use custom_properties_json instead to keep their type.",
+				Optional:      true,
+				ConflictsWith: []string{"custom_properties_json"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"custom_properties_json": {
+				Type:             schema.TypeString,
+				Description:      "A JSON object specifying additional properties for the job profile, which can hold values of any type. Differences in key order, whitespace and numeric formatting do not produce a plan.",
+				Optional:         true,
+				ConflictsWith:    []string{"custom_properties"},
+				ValidateFunc:     validateJsonObject,
+				DiffSuppressFunc: suppressEquivalentJsonValueDiffs,
+			},
+		},
+	}
+}
+
+func jobInputParameterResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "The name of the input parameter.",
+				Required:    true,
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Description: "The type of the input parameter. Should specify an MCMA resource or primitive type.",
+				Required:    true,
+			},
+			"optional": {
+				Type:        schema.TypeBool,
+				Description: "Flag indicating if this input parameter must be provided or not",
+				Optional:    true,
+				Default:     false,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Description: "A description of the input parameter.",
+				Optional:    true,
+			},
+			"default_value": {
+				Type:        schema.TypeString,
+				Description: "The value used for the input parameter when it is not provided.",
+				Optional:    true,
+			},
+			"allowed_values": {
+				Type:        schema.TypeList,
+				Description: "The values that are allowed for the input parameter.",
 				Optional:    true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
@@ -103,15 +162,70 @@ func resourceJobProfile() *schema.Resource {
 	}
 }
 
-func getJobProfileFromResourceData(d *schema.ResourceData) mcmamodel.JobProfile {
-	var inputParameters []mcmamodel.JobParameter
-	var optionalInputParameters []mcmamodel.JobParameter
-	for _, raw := range d.Get("input_parameter").(*schema.Set).List() {
-		pMap := raw.(map[string]interface{})
-		inputParameter := mcmamodel.JobParameter{
-			ParameterType: pMap["type"].(string),
-			ParameterName: pMap["name"].(string),
+func jobOutputParameterResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Description: "The name of the output parameter.",
+				Required:    true,
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Description: "The type of the output parameter. Should specify an MCMA resource or primitive type.",
+				Required:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Description: "A description of the output parameter.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// jobParametersAttribute returns the attribute holding the input or output parameters of a job profile, i.e. the
+// ordered list if it is used, and the set otherwise.
+func jobParametersAttribute(d *schema.ResourceData, kind string) string {
+	if ordered := d.Get("ordered_" + kind + "_parameter").([]interface{}); len(ordered) > 0 {
+		return "ordered_" + kind + "_parameter"
+	}
+	return kind + "_parameter"
+}
+
+// getJobParameterMaps returns the input or output parameters of a job profile in the order in which they are stored.
+func getJobParameterMaps(d *schema.ResourceData, kind string) []interface{} {
+	switch parameters := d.Get(jobParametersAttribute(d, kind)).(type) {
+	case *schema.Set:
+		return parameters.List()
+	default:
+		return parameters.([]interface{})
+	}
+}
+
+func getJobParameterFromMap(p map[string]interface{}) jobParameterDocument {
+	parameter := jobParameterDocument{
+		ParameterType: p["type"].(string),
+		ParameterName: p["name"].(string),
+		Description:   p["description"].(string),
+	}
+	if defaultValue, ok := p["default_value"].(string); ok {
+		parameter.DefaultValue = defaultValue
+	}
+	if allowedValues, ok := p["allowed_values"].([]interface{}); ok {
+		for _, v := range allowedValues {
+			parameter.AllowedValues = append(parameter.AllowedValues, v.(string))
 		}
+	}
+	return parameter
+}
+
+func getJobProfileFromResourceData(d *schema.ResourceData) (jobProfileDocument, error) {
+	var inputParameters []jobParameterDocument
+	var optionalInputParameters []jobParameterDocument
+	for _, raw := range getJobParameterMaps(d, "input") {
+		pMap := raw.(map[string]interface{})
+		inputParameter := getJobParameterFromMap(pMap)
 		if pMap["optional"].(bool) {
 			optionalInputParameters = append(optionalInputParameters, inputParameter)
 		} else {
@@ -119,33 +233,168 @@ func getJobProfileFromResourceData(d *schema.ResourceData) mcmamodel.JobProfile
 		}
 	}
 
-	var outputParameters []mcmamodel.JobParameter
-	for _, p := range d.Get("output_parameter").(*schema.Set).List() {
-		outputParameter := p.(map[string]interface{})
-		outputParameters = append(outputParameters, mcmamodel.JobParameter{
-			ParameterType: outputParameter["type"].(string),
-			ParameterName: outputParameter["name"].(string),
-		})
+	var outputParameters []jobParameterDocument
+	for _, p := range getJobParameterMaps(d, "output") {
+		outputParameters = append(outputParameters, getJobParameterFromMap(p.(map[string]interface{})))
+	}
+
+	custom := d.Get("custom_properties").(map[string]interface{})
+	if customJson := d.Get("custom_properties_json").(string); customJson != "" {
+		custom = make(map[string]interface{})
+		if err := json.Unmarshal([]byte(customJson), &custom); err != nil {
+			return jobProfileDocument{}, fmt.Errorf("error parsing custom_properties_json: %s", err)
+		}
 	}
 
-	return mcmamodel.JobProfile{
+	return jobProfileDocument{
 		Type:                    "JobProfile",
+		Id:                      d.Id(),
+		DateCreated:             optionalDate(parseDate(d.Get("date_created").(string))),
+		DateModified:            optionalDate(parseDate(d.Get("date_modified").(string))),
 		Name:                    d.Get("name").(string),
 		InputParameters:         inputParameters,
 		OutputParameters:        outputParameters,
 		OptionalInputParameters: optionalInputParameters,
-		Custom:                  d.Get("custom_properties").(map[string]interface{}),
+		Custom:                  custom,
+	}, nil
+}
+
+func flattenJobParameter(parameter jobParameterDocument) map[string]interface{} {
+	p := make(map[string]interface{})
+	p["type"] = parameter.ParameterType
+	p["name"] = parameter.ParameterName
+	p["description"] = parameter.Description
+	return p
+}
+
+func flattenJobInputParameter(parameter jobParameterDocument, optional bool) map[string]interface{} {
+	p := flattenJobParameter(parameter)
+	p["optional"] = optional
+	p["default_value"] = parameter.DefaultValue
+	allowedValues := make([]interface{}, 0, len(parameter.AllowedValues))
+	for _, v := range parameter.AllowedValues {
+		allowedValues = append(allowedValues, v)
+	}
+	p["allowed_values"] = allowedValues
+	return p
+}
+
+// flattenJobProfileInputParameters returns the required and optional input parameters of a job profile as a single
+// list, using the optional flag to tell them apart.
+func flattenJobProfileInputParameters(jobProfile jobProfileDocument) []map[string]interface{} {
+	var inputParameters []map[string]interface{}
+	for _, inputParameter := range jobProfile.InputParameters {
+		inputParameters = append(inputParameters, flattenJobInputParameter(inputParameter, false))
+	}
+	for _, optionalInputParameter := range jobProfile.OptionalInputParameters {
+		inputParameters = append(inputParameters, flattenJobInputParameter(optionalInputParameter, true))
+	}
+	return inputParameters
+}
+
+func flattenJobProfileOutputParameters(jobProfile jobProfileDocument) []map[string]interface{} {
+	var outputParameters []map[string]interface{}
+	for _, outputParameter := range jobProfile.OutputParameters {
+		outputParameters = append(outputParameters, flattenJobParameter(outputParameter))
 	}
+	return outputParameters
 }
 
-func resourceJobProfileRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+// resourceJobProfileCustomizeDiff rejects ordered parameters that can't be stored in the declared order.
+func resourceJobProfileCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	optionalName := ""
+	for _, p := range d.Get("ordered_input_parameter").([]interface{}) {
+		pMap, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if pMap["optional"].(bool) {
+			if optionalName == "" {
+				optionalName = pMap["name"].(string)
+			}
+		} else if optionalName != "" {
+			return fmt.Errorf("ordered_input_parameter %s must be declared before optional ordered_input_parameter %s", pMap["name"], optionalName)
+		}
+	}
+	return nil
+}
+
+// setJobProfileCustomProperties writes the custom properties of a job profile to custom_properties_json if it is used
+// in state, and to custom_properties otherwise, JSON encoding the values that are not strings so that the properties
+// of a job profile written by another tool do not produce a plan.
+func setJobProfileCustomProperties(d *schema.ResourceData, jobProfile jobProfileDocument) diag.Diagnostics {
+	if d.Get("custom_properties_json").(string) == "" {
+		if err := d.Set("custom_properties", flattenCustomProperties(jobProfile.Custom)); err != nil {
+			return diag.Errorf("error setting custom_properties for job profile with id %s: %s", jobProfile.Id, err)
+		}
+		return diag.Diagnostics{}
+	}
+
+	custom := jobProfile.Custom
+	if custom == nil {
+		custom = make(map[string]interface{})
+	}
+	jsonBytes, err := json.Marshal(custom)
+	if err != nil {
+		return diag.Errorf("error encoding custom properties for job profile with id %s: %s", jobProfile.Id, err)
+	}
+	_ = d.Set("custom_properties", nil)
+	_ = d.Set("custom_properties_json", string(jsonBytes))
+	return diag.Diagnostics{}
+}
+
+// hasNonStringCustomProperties returns whether any of the custom properties of a job profile would be JSON encoded in
+// custom_properties.
+func hasNonStringCustomProperties(jobProfile jobProfileDocument) bool {
+	for _, value := range jobProfile.Custom {
+		if _, ok := value.(string); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setJobProfileResourceData writes every attribute of a job profile returned by the service registry to state.
+func setJobProfileResourceData(d *schema.ResourceData, jobProfile jobProfileDocument) diag.Diagnostics {
+	d.SetId(jobProfile.Id)
+	_ = d.Set("type", jobProfile.Type)
+	_ = d.Set("date_created", formatDate(dateValue(jobProfile.DateCreated)))
+	_ = d.Set("date_modified", formatDate(dateValue(jobProfile.DateModified)))
+	_ = d.Set("name", jobProfile.Name)
+	if diags := setJobProfileCustomProperties(d, jobProfile); diags.HasError() {
+		return diags
+	}
+
+	// the parameters are read back into the attributes that hold them in state, the sets unless the ordered lists are
+	// used, e.g. when importing
+	inputAttribute := jobParametersAttribute(d, "input")
+	if err := d.Set(inputAttribute, flattenJobProfileInputParameters(jobProfile)); err != nil {
+		return diag.Errorf("error setting %s for job profile with id %s: %s", inputAttribute, jobProfile.Id, err)
+	}
+
+	outputAttribute := jobParametersAttribute(d, "output")
+	if err := d.Set(outputAttribute, flattenJobProfileOutputParameters(jobProfile)); err != nil {
+		return diag.Errorf("error setting %s for job profile with id %s: %s", outputAttribute, jobProfile.Id, err)
+	}
+
+	return diag.Diagnostics{}
+}
+
+func resourceJobProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	resourceManager, di := getResourceManager(m)
 	if di != nil {
 		return di
 	}
 
+	// the untyped functions are used so that the documentation of the parameters, which mcmamodel.JobParameter does
+	// not have, is read back
 	jobProfileId := d.Id()
-	resource, err := resourceManager.Get(reflect.TypeOf(mcmamodel.JobProfile{}), jobProfileId)
+	get := getMcmaResourceVersion(ctx, resourceManager, "JobProfile", jobProfileId)
+	resource, _, err := get()
+	if err == nil && resource == nil && d.IsNewResource() {
+		// a job profile that was just created may not be returned by the service registry straight away
+		resource, err = readAfterWrite(ctx, "job profile "+jobProfileId, time.Time{}, get)
+	}
 	if err != nil {
 		return diag.Errorf("error getting job profile with id %s: %s", jobProfileId, err)
 	}
@@ -154,46 +403,21 @@ func resourceJobProfileRead(_ context.Context, d *schema.ResourceData, m interfa
 		return diag.Diagnostics{}
 	}
 
-	jobProfile := resource.(mcmamodel.JobProfile)
-
-	_ = d.Set("type", jobProfile.Type)
-	_ = d.Set("id", jobProfile.Id)
-	_ = d.Set("date_created", jobProfile.DateCreated.Format(time.RFC3339))
-	_ = d.Set("date_modified", jobProfile.DateModified.Format(time.RFC3339))
-	_ = d.Set("name", jobProfile.Name)
-	_ = d.Set("custom_properties", jobProfile.Custom)
-
-	var inputParameters []map[string]interface{}
-	for _, inputParameter := range jobProfile.InputParameters {
-		p := make(map[string]interface{})
-		p["type"] = inputParameter.ParameterType
-		p["name"] = inputParameter.ParameterName
-		p["optional"] = false
-		inputParameters = append(inputParameters, p)
-	}
-	for _, optionalInputParameter := range jobProfile.OptionalInputParameters {
-		p := make(map[string]interface{})
-		p["type"] = optionalInputParameter.ParameterType
-		p["name"] = optionalInputParameter.ParameterName
-		p["optional"] = true
-		inputParameters = append(inputParameters, p)
-	}
-	if err = d.Set("input_parameter", inputParameters); err != nil {
-		return diag.Errorf("error setting input_parameter for job profile with id %s: %s", jobProfileId, err)
+	if di = setJobProfileResourceDataFromMap(d, resource.(map[string]interface{})); di.HasError() {
+		return di
 	}
+	refreshKnownDateModified(d, resourceJobProfile())
+	return di
+}
 
-	var outputParameters []map[string]interface{}
-	for _, outputParameter := range jobProfile.OutputParameters {
-		p := make(map[string]interface{})
-		p["type"] = outputParameter.ParameterType
-		p["name"] = outputParameter.ParameterName
-		outputParameters = append(outputParameters, p)
-	}
-	if err = d.Set("output_parameter", outputParameters); err != nil {
-		return diag.Errorf("error setting output_parameter for job profile with id %s: %s", jobProfileId, err)
+func setJobProfileResourceDataFromMap(d *schema.ResourceData, resource map[string]interface{}) diag.Diagnostics {
+	jobProfileId, _ := resource["id"].(string)
+	jobProfile, err := jobProfileDocumentFromMap(resource)
+	if err != nil {
+		return diag.Errorf("error parsing job profile with id %s: %s", jobProfileId, err)
 	}
 
-	return diag.Diagnostics{}
+	return setJobProfileResourceData(d, jobProfile)
 }
 
 func resourceJobProfileCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -201,14 +425,21 @@ func resourceJobProfileCreate(ctx context.Context, d *schema.ResourceData, m int
 	if di != nil {
 		return di
 	}
-	jobProfile := getJobProfileFromResourceData(d)
-	createdResource, err := resourceManager.Create(jobProfile)
+	jobProfile, err := getJobProfileFromResourceData(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	resource, err := jobProfile.toMap()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	createdResource, err := resourceManager.Create(ctx, resource)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	createdJobProfile := createdResource.(mcmamodel.JobProfile)
+	createdResourceMap := createdResource.(map[string]interface{})
 
-	d.SetId(createdJobProfile.Id)
+	d.SetId(createdResourceMap["id"].(string))
 
 	return resourceJobProfileRead(ctx, d, m)
 }
@@ -219,33 +450,97 @@ func resourceJobProfileUpdate(ctx context.Context, d *schema.ResourceData, m int
 		return di
 	}
 
-	jobProfile := getJobProfileFromResourceData(d)
-	jobProfile.Id = d.Id()
+	if getConflictDetection(m) {
+		di = checkForConflict(d, resourceJobProfile(), "job profile", func(remote *schema.ResourceData) diag.Diagnostics {
+			return resourceJobProfileRead(ctx, remote, m)
+		})
+		if di != nil {
+			return di
+		}
+	}
 
-	if dc, err := time.Parse(time.RFC3339, d.Get("date_created").(string)); err == nil {
-		jobProfile.DateCreated = dc
-	} else {
-		jobProfile.DateCreated = time.Now().UTC()
+	jobProfile, err := getJobProfileFromResourceData(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if jobProfile.DateCreated == nil {
+		jobProfile.DateCreated = optionalDate(time.Now().UTC())
+	}
+	resource, err := jobProfile.toMap()
+	if err != nil {
+		return diag.FromErr(err)
 	}
 
-	_, err := resourceManager.Update(jobProfile)
+	updatedResource, err := resourceManager.Update(ctx, resource)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceJobProfileRead(ctx, d, m)
+	jobProfileId := d.Id()
+	readResource, err := readAfterWrite(ctx, "job profile "+jobProfileId, getMcmaResourceDateModified(updatedResource), getMcmaResourceVersion(ctx, resourceManager, "JobProfile", jobProfileId))
+	if err != nil {
+		return diag.Errorf("error getting job profile with id %s: %s", jobProfileId, err)
+	}
+
+	if di = setJobProfileResourceDataFromMap(d, readResource.(map[string]interface{})); di.HasError() {
+		return di
+	}
+	setKnownDateModified(d)
+	return di
 }
 
-func resourceJobProfileDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceJobProfileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	resourceManager, di := getResourceManager(m)
 	if di != nil {
 		return di
 	}
 
-	err := resourceManager.Delete(reflect.TypeOf(mcmamodel.JobProfile{}), d.Id())
+	jobProfileId := d.Id()
+	err := resourceManager.Delete(ctx, reflect.TypeOf(mcmamodel.JobProfile{}), jobProfileId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = waitUntilDeleted(ctx, "job profile "+jobProfileId, getMcmaResourceVersion(ctx, resourceManager, "JobProfile", jobProfileId))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diag.Diagnostics{}
 }
+
+// resourceJobProfileImport accepts either the ID of a job profile or its name, in which case the ID is resolved through
+// the service registry. The custom properties of a job profile that has values that are not strings are imported in
+// custom_properties_json, so that these values keep their type.
+func resourceJobProfileImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return nil, diagsToError(di)
+	}
+
+	if !isAbsoluteUrl(d.Id()) {
+		name := d.Id()
+		jobProfile, di := getJobProfileByName(ctx, resourceManager, name)
+		if di != nil {
+			return nil, diagsToError(di)
+		}
+		if jobProfile == nil {
+			return nil, fmt.Errorf("job profile with name %s not found", name)
+		}
+		d.SetId(jobProfile.Id)
+	}
+
+	jobProfile, err := getJobProfileDocument(ctx, resourceManager, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	if jobProfile != nil && hasNonStringCustomProperties(*jobProfile) {
+		jsonBytes, err := json.Marshal(jobProfile.Custom)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding custom properties for job profile with id %s: %s", jobProfile.Id, err)
+		}
+		_ = d.Set("custom_properties_json", string(jsonBytes))
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,126 @@
+package mcma
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceJobProfileV0 is the schema of mcma_job_profile before custom_properties_json was added.
+func resourceJobProfileV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"date_created": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"date_modified": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"input_parameter": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"optional": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+			"output_parameter": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"custom_properties": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// resourceJobProfileStateUpgradeV0 upgrades the state of job profiles created before custom_properties_json existed.
+// Their custom properties stay in custom_properties, as they are read back unless custom_properties_json is set, with
+// any values that are not strings JSON encoded like they are on read, and custom_properties_json is set to empty.
+func resourceJobProfileStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if customProperties, ok := rawState["custom_properties"].(map[string]interface{}); ok {
+		rawState["custom_properties"] = flattenCustomProperties(customProperties)
+	}
+	rawState["custom_properties_json"] = ""
+	return rawState, nil
+}
+
+// resourceJobProfileV1 is the schema of mcma_job_profile before parameters had documentation and could be ordered.
+func resourceJobProfileV1() *schema.Resource {
+	r := resourceJobProfileV0()
+	r.Schema["custom_properties"].ConflictsWith = []string{"custom_properties_json"}
+	r.Schema["custom_properties_json"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ConflictsWith: []string{"custom_properties"},
+	}
+	return r
+}
+
+// resourceJobProfileStateUpgradeV1 upgrades the state of job profiles created before parameters had a description,
+// a default value and allowed values. The parameters stay in the input_parameter and output_parameter sets, which
+// keep their semantics, with the new attributes empty like they are when a configuration does not set them, so that
+// an unchanged configuration plans no changes.
+func resourceJobProfileStateUpgradeV1(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if inputParameters, ok := rawState["input_parameter"].([]interface{}); ok {
+		for _, p := range inputParameters {
+			if pMap, ok := p.(map[string]interface{}); ok {
+				pMap["description"] = ""
+				pMap["default_value"] = ""
+				pMap["allowed_values"] = []interface{}{}
+			}
+		}
+	}
+	if outputParameters, ok := rawState["output_parameter"].([]interface{}); ok {
+		for _, p := range outputParameters {
+			if pMap, ok := p.(map[string]interface{}); ok {
+				pMap["description"] = ""
+			}
+		}
+	}
+	return rawState, nil
+}
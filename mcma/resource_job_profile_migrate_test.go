@@ -0,0 +1,195 @@
+package mcma
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceJobProfileStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":   "https://service-registry.mcma.io/api/job-profiles/1",
+		"name": "ExtractTechnicalMetadata",
+		"custom_properties": map[string]interface{}{
+			"tier":     "premium",
+			"priority": float64(2),
+		},
+	}
+
+	expected := map[string]interface{}{
+		"id":   "https://service-registry.mcma.io/api/job-profiles/1",
+		"name": "ExtractTechnicalMetadata",
+		"custom_properties": map[string]interface{}{
+			"tier":     "premium",
+			"priority": "2",
+		},
+		"custom_properties_json": "",
+	}
+
+	actual, err := resourceJobProfileStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error upgrading state: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+// TestResourceJobProfileStateUpgradeV0_read upgrades the state of a job profile created with version 0 of the schema
+// and checks that reading the job profile back, now that it has custom properties that are not strings, leaves the
+// custom properties in state as they were.
+func TestResourceJobProfileStateUpgradeV0_read(t *testing.T) {
+	var rawState map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"type": "JobProfile",
+		"id": "https://service-registry.mcma.io/api/job-profiles/1",
+		"date_created": "2022-03-01T10:00:00Z",
+		"date_modified": "2022-03-02T11:30:00Z",
+		"name": "ExtractTechnicalMetadata",
+		"input_parameter": [{"name": "inputFile", "type": "Locator", "optional": false}],
+		"output_parameter": [{"name": "outputFile", "type": "Locator"}],
+		"custom_properties": {"tier": "premium", "priority": "2"}
+	}`), &rawState)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := resourceJobProfile()
+	for _, upgrader := range r.StateUpgraders {
+		if rawState, err = upgrader.Upgrade(context.Background(), rawState, nil); err != nil {
+			t.Fatalf("error upgrading state from version %d: %s", upgrader.Version, err)
+		}
+	}
+	stateJson, err := json.Marshal(rawState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateValue, err := ctyjson.Unmarshal(stateJson, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding upgraded state: %s", err)
+	}
+	d := r.Data(terraform.NewInstanceStateShimmedFromValue(stateValue, r.SchemaVersion))
+	upgraded := d.State().Attributes
+
+	di := setJobProfileResourceData(d, jobProfileDocument{
+		Type:             "JobProfile",
+		Id:               "https://service-registry.mcma.io/api/job-profiles/1",
+		DateCreated:      optionalDate(parseDate("2022-03-01T10:00:00Z")),
+		DateModified:     optionalDate(parseDate("2022-03-02T11:30:00Z")),
+		Name:             "ExtractTechnicalMetadata",
+		InputParameters:  []jobParameterDocument{{ParameterName: "inputFile", ParameterType: "Locator"}},
+		OutputParameters: []jobParameterDocument{{ParameterName: "outputFile", ParameterType: "Locator"}},
+		Custom:           map[string]interface{}{"tier": "premium", "priority": float64(2)},
+	})
+	if di.HasError() {
+		t.Fatalf("error reading job profile: %v", di)
+	}
+
+	if read := d.State().Attributes; !reflect.DeepEqual(upgraded, read) {
+		t.Errorf("expected state %v to be read back unchanged, got %v", upgraded, read)
+	}
+}
+
+func TestResourceJobProfileStateUpgradeV1(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":   "https://service-registry.mcma.io/api/job-profiles/1",
+		"name": "ExtractTechnicalMetadata",
+		"input_parameter": []interface{}{
+			map[string]interface{}{"name": "inputFile", "type": "Locator", "optional": false},
+		},
+		"output_parameter": []interface{}{
+			map[string]interface{}{"name": "outputFile", "type": "Locator"},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"id":   "https://service-registry.mcma.io/api/job-profiles/1",
+		"name": "ExtractTechnicalMetadata",
+		"input_parameter": []interface{}{
+			map[string]interface{}{
+				"name":           "inputFile",
+				"type":           "Locator",
+				"optional":       false,
+				"description":    "",
+				"default_value":  "",
+				"allowed_values": []interface{}{},
+			},
+		},
+		"output_parameter": []interface{}{
+			map[string]interface{}{"name": "outputFile", "type": "Locator", "description": ""},
+		},
+	}
+
+	actual, err := resourceJobProfileStateUpgradeV1(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error upgrading state: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+// TestResourceJobProfileStateUpgradeV1_plan upgrades the state of a job profile created with version 1 of the schema
+// and checks that planning the configuration it was created with plans no changes.
+func TestResourceJobProfileStateUpgradeV1_plan(t *testing.T) {
+	var rawState map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"type": "JobProfile",
+		"id": "https://service-registry.mcma.io/api/job-profiles/1",
+		"date_created": "2022-03-01T10:00:00Z",
+		"date_modified": "2022-03-02T11:30:00Z",
+		"name": "ExtractTechnicalMetadata",
+		"input_parameter": [
+			{"name": "inputFile", "type": "Locator", "optional": false},
+			{"name": "format", "type": "string", "optional": true}
+		],
+		"output_parameter": [{"name": "outputFile", "type": "Locator"}],
+		"custom_properties": {"tier": "premium"},
+		"custom_properties_json": ""
+	}`), &rawState)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := resourceJobProfile()
+	for _, upgrader := range r.StateUpgraders {
+		if upgrader.Version < 1 {
+			continue
+		}
+		if rawState, err = upgrader.Upgrade(context.Background(), rawState, nil); err != nil {
+			t.Fatalf("error upgrading state from version %d: %s", upgrader.Version, err)
+		}
+	}
+	stateJson, err := json.Marshal(rawState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateValue, err := ctyjson.Unmarshal(stateJson, r.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding upgraded state: %s", err)
+	}
+	state := terraform.NewInstanceStateShimmedFromValue(stateValue, r.SchemaVersion)
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "ExtractTechnicalMetadata",
+		"input_parameter": []interface{}{
+			map[string]interface{}{"name": "format", "type": "string", "optional": true},
+			map[string]interface{}{"name": "inputFile", "type": "Locator"},
+		},
+		"output_parameter": []interface{}{
+			map[string]interface{}{"name": "outputFile", "type": "Locator"},
+		},
+		"custom_properties": map[string]interface{}{"tier": "premium"},
+	})
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("error planning: %s", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no changes to be planned, got %v", diff.Attributes)
+	}
+}
@@ -1,8 +1,10 @@
 package mcma
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
@@ -10,12 +12,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
-	mcmaclient "github.com/ebu/mcma-libraries-go/client"
-	mcmamodel "github.com/ebu/mcma-libraries-go/model"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
 )
 
 func TestAccMcmaJobProfile_basic(t *testing.T) {
-	profileName := acctest.RandStringFromCharSet(5, acctest.CharSetAlpha)
+	profileName := acctest.RandomWithPrefix(testAccNamePrefix)
 	createTestCase := func(providerConfig string) resource.TestCase {
 		return resource.TestCase{
 			Providers: testAccProviders,
@@ -37,6 +38,17 @@ func TestAccMcmaJobProfile_basic(t *testing.T) {
 						testAccCheckJobProfileExists("mcma_job_profile.job_profile_"+profileName+"_3"),
 					),
 				},
+				{
+					ResourceName:      "mcma_job_profile.job_profile_" + profileName + "_1",
+					ImportState:       true,
+					ImportStateVerify: true,
+				},
+				{
+					ResourceName:      "mcma_job_profile.job_profile_" + profileName + "_2",
+					ImportState:       true,
+					ImportStateId:     profileName + "_2",
+					ImportStateVerify: true,
+				},
 			},
 		}
 	}
@@ -45,19 +57,92 @@ func TestAccMcmaJobProfile_basic(t *testing.T) {
 	resource.Test(t, createTestCase(getMcmaApiKeyProviderConfigFromEnvVars()))
 }
 
+func TestAccMcmaJobProfile_orderedParameters(t *testing.T) {
+	profileName := acctest.RandomWithPrefix(testAccNamePrefix)
+	resourceName := "mcma_job_profile.job_profile_" + profileName
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaJobProfileDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaJobProfileOrderedParameters(profileName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						testAccCheckJobProfileExists(resourceName),
+						resource.TestCheckResourceAttr(resourceName, "ordered_input_parameter.0.name", "inputFile"),
+						resource.TestCheckResourceAttr(resourceName, "ordered_input_parameter.0.description", "The file to transcode"),
+						resource.TestCheckResourceAttr(resourceName, "ordered_input_parameter.1.name", "format"),
+						resource.TestCheckResourceAttr(resourceName, "ordered_input_parameter.1.default_value", "mp4"),
+						resource.TestCheckResourceAttr(resourceName, "ordered_input_parameter.1.allowed_values.#", "2"),
+						resource.TestCheckResourceAttr(resourceName, "ordered_input_parameter.2.name", "bitrate"),
+						resource.TestCheckResourceAttr(resourceName, "ordered_output_parameter.0.description", "The transcoded file"),
+						resource.TestCheckNoResourceAttr(resourceName, "input_parameter.#"),
+					),
+				},
+				{
+					// imported job profiles have their parameters in the input_parameter and output_parameter sets
+					ResourceName:      resourceName,
+					ImportState:       true,
+					ImportStateVerify: true,
+					ImportStateVerifyIgnore: []string{
+						"input_parameter",
+						"output_parameter",
+						"ordered_input_parameter",
+						"ordered_output_parameter",
+					},
+				},
+				{
+					Config:      testAccountMcmaJobProfileOptionalParameterFirst(profileName, providerConfig),
+					ExpectError: regexp.MustCompile("ordered_input_parameter format must be declared before optional ordered_input_parameter bitrate"),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+}
+
+func TestAccMcmaJobProfile_customPropertiesJson(t *testing.T) {
+	profileName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaJobProfileDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaJobProfileCustomPropertiesJson(profileName, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						testAccCheckJobProfileExists("mcma_job_profile.job_profile_"+profileName),
+						resource.TestCheckResourceAttr("mcma_job_profile.job_profile_"+profileName, "custom_properties.%", "0"),
+					),
+				},
+				{
+					ResourceName:      "mcma_job_profile.job_profile_" + profileName,
+					ImportState:       true,
+					ImportStateVerify: true,
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+}
+
 func testAccCheckMcmaJobProfileDestroy(s *terraform.State) error {
-	resourceManager := testAccProvider.Meta().(*mcmaclient.ResourceManager)
+	resourceManager := testAccProvider.Meta().(*providerMeta).resourceManager
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "mcma_job_profile" {
 			continue
 		}
-		existing, err := resourceManager.Get(reflect.TypeOf(mcmamodel.JobProfile{}), rs.Primary.ID)
+		existing, err := resourceManager.Get(context.Background(), reflect.TypeOf(mcmamodel.JobProfile{}), rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 		for i := 0; existing != nil && i < 30; i++ {
 			time.Sleep(1 * time.Second)
-			existing, err = resourceManager.Get(reflect.TypeOf(mcmamodel.JobProfile{}), rs.Primary.ID)
+			existing, err = resourceManager.Get(context.Background(), reflect.TypeOf(mcmamodel.JobProfile{}), rs.Primary.ID)
 			if err != nil {
 				return err
 			}
@@ -181,6 +266,78 @@ resource "mcma_job_profile" "job_profile_%s_3" {
 `, providerConfig, profileName, profileName, profileName, profileName, profileName, profileName)
 }
 
+func testAccountMcmaJobProfileCustomPropertiesJson(profileName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job_profile" "job_profile_%s" {
+  name = "%s"
+  input_parameter {
+	name = "param1"
+	type = "string"
+  }
+  custom_properties_json = jsonencode({
+	tier = "premium"
+	priority = 2
+	gpu = true
+	limits = {
+	  maxDuration = 3600
+	}
+  })
+}
+`, providerConfig, profileName, profileName)
+}
+
+func testAccountMcmaJobProfileOrderedParameters(profileName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job_profile" "job_profile_%s" {
+  name = "%s"
+  ordered_input_parameter {
+	name = "inputFile"
+	type = "Locator"
+	description = "The file to transcode"
+  }
+  ordered_input_parameter {
+	name = "format"
+	type = "string"
+	default_value = "mp4"
+	allowed_values = ["mp4", "mov"]
+  }
+  ordered_input_parameter {
+	name = "bitrate"
+	type = "number"
+	optional = true
+  }
+  ordered_output_parameter {
+	name = "outputFile"
+	type = "Locator"
+	description = "The transcoded file"
+  }
+}
+`, providerConfig, profileName, profileName)
+}
+
+func testAccountMcmaJobProfileOptionalParameterFirst(profileName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job_profile" "job_profile_%s" {
+  name = "%s"
+  ordered_input_parameter {
+	name = "bitrate"
+	type = "number"
+	optional = true
+  }
+  ordered_input_parameter {
+	name = "format"
+	type = "string"
+  }
+}
+`, providerConfig, profileName, profileName)
+}
+
 func testAccCheckJobProfileExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -190,8 +347,8 @@ func testAccCheckJobProfileExists(resourceName string) resource.TestCheckFunc {
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("job profile ID not set")
 		}
-		resourceManager := testAccProvider.Meta().(*mcmaclient.ResourceManager)
-		p, err := resourceManager.Get(reflect.TypeOf(mcmamodel.JobProfile{}), rs.Primary.ID)
+		resourceManager := testAccProvider.Meta().(*providerMeta).resourceManager
+		p, err := resourceManager.Get(context.Background(), reflect.TypeOf(mcmamodel.JobProfile{}), rs.Primary.ID)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,256 @@
+package mcma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/ebu/terraform-provider-mcma/mcmatest"
+)
+
+func TestGetJobFromResourceData(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceJob().Schema, map[string]interface{}{
+		"job_type":       "AmeJob",
+		"job_profile_id": "https://service-registry.mcma.io/api/job-profiles/1",
+		"job_input":      `{"inputFile": {"@type": "S3Locator", "url": "s3://bucket/file.mp4"}}`,
+	})
+
+	job, err := getJobFromResourceData(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"@type":        "AmeJob",
+		"jobProfileId": "https://service-registry.mcma.io/api/job-profiles/1",
+		"jobInput": map[string]interface{}{
+			"@type":     "JobParameterBag",
+			"inputFile": map[string]interface{}{"@type": "S3Locator", "url": "s3://bucket/file.mp4"},
+		},
+	}
+	if !reflect.DeepEqual(job, expected) {
+		t.Errorf("expected %v, got %v", expected, job)
+	}
+}
+
+func TestSetJobResourceData(t *testing.T) {
+	var job map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"@type": "AmeJob",
+		"id": "https://service.mcma.io/api/jobs/1",
+		"dateCreated": "2022-03-01T10:00:00Z",
+		"dateModified": "2022-03-01T10:05:00Z",
+		"jobProfileId": "https://service-registry.mcma.io/api/job-profiles/1",
+		"jobInput": {"@type": "JobParameterBag", "inputFile": {"@type": "S3Locator", "url": "s3://bucket/file.mp4"}},
+		"status": "Failed",
+		"progress": 42.5,
+		"jobOutput": {"@type": "JobParameterBag", "partial": true},
+		"error": {"@type": "ProblemDetail", "type": "uri://mcma.ebu.ch/rfc7807/ame/failure", "title": "Analysis failed"}
+	}`), &job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceJob().Schema, nil)
+	if di := setJobResourceData(d, job); di.HasError() {
+		t.Fatalf("error setting job: %v", di)
+	}
+
+	expected := map[string]string{
+		"id":             "https://service.mcma.io/api/jobs/1",
+		"job_type":       "AmeJob",
+		"job_profile_id": "https://service-registry.mcma.io/api/job-profiles/1",
+		"date_created":   "2022-03-01T10:00:00Z",
+		"date_modified":  "2022-03-01T10:05:00Z",
+		"job_input":      `{"inputFile":{"@type":"S3Locator","url":"s3://bucket/file.mp4"}}`,
+		"status":         "Failed",
+		"progress":       "42.5",
+		"job_output":     `{"partial":true}`,
+		"error":          `{"@type":"ProblemDetail","title":"Analysis failed","type":"uri://mcma.ebu.ch/rfc7807/ame/failure"}`,
+	}
+	attributes := d.State().Attributes
+	for k, v := range expected {
+		if attributes[k] != v {
+			t.Errorf("attribute %s: expected %q, got %q", k, v, attributes[k])
+		}
+	}
+}
+
+func TestIsJobFinished(t *testing.T) {
+	for status, expected := range map[string]bool{
+		"New":       false,
+		"Running":   false,
+		"Completed": true,
+		"COMPLETED": true,
+		"Failed":    true,
+		"Canceled":  true,
+	} {
+		if isJobFinished(status) != expected {
+			t.Errorf("expected isJobFinished(%q) to be %t", status, expected)
+		}
+	}
+}
+
+// TestAccMcmaJob_basic runs a job against a job profile that is already registered. Against a real registry, the job
+// type and profile are set up by the service under test and must be set in the environment, e.g.
+// MCMA_TEST_JOB_TYPE=AmeJob and MCMA_TEST_JOB_PROFILE_NAME=ExtractTechnicalMetadata. The fake registry has its own.
+func TestAccMcmaJob_basic(t *testing.T) {
+	jobType := os.Getenv("MCMA_TEST_JOB_TYPE")
+	jobProfileName := os.Getenv("MCMA_TEST_JOB_PROFILE_NAME")
+	jobInput := os.Getenv("MCMA_TEST_JOB_INPUT")
+	if os.Getenv("MCMA_AWS_SERVICE_REGISTRY_URL") == "" {
+		jobType, jobProfileName, jobInput = fakeJobType, fakeJobProfileName, `{"inputFile": "s3://bucket/file.mp4"}`
+	}
+	if jobType == "" || jobProfileName == "" {
+		t.Skip("MCMA_TEST_JOB_TYPE and MCMA_TEST_JOB_PROFILE_NAME must be set to run job acceptance tests")
+	}
+
+	jobName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaJob(jobName, jobType, jobProfileName, jobInput, providerConfig),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("mcma_job.job_"+jobName, "status", jobStatusCompleted),
+						resource.TestCheckResourceAttrSet("mcma_job.job_"+jobName, "job_profile_id"),
+						resource.TestCheckResourceAttrSet("mcma_job.job_"+jobName, "job_output"),
+					),
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+}
+
+// TestAccMcmaJob_polling runs a job that is still running when it is submitted against a fake registry, and checks
+// that the provider polls it until it has completed.
+func TestAccMcmaJob_polling(t *testing.T) {
+	registry := newDefaultFakeRegistry(mcmatest.AuthTypeMcmaApiKey)
+	defer registry.Close()
+	registry.SetJobDuration(3 * time.Second)
+
+	jobName := acctest.RandomWithPrefix(testAccNamePrefix)
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccountMcmaJob(jobName, fakeJobType, fakeJobProfileName, `{"inputFile": "s3://bucket/file.mp4"}`, registry.ProviderConfig()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mcma_job.job_"+jobName, "status", jobStatusCompleted),
+					resource.TestCheckResourceAttrSet("mcma_job.job_"+jobName, "job_output"),
+					testAccCheckJobPolled(registry, "mcma_job.job_"+jobName),
+				),
+			},
+		},
+	})
+}
+
+// TestAccMcmaJob_cancelOnDestroy submits a job that never finishes to a fake registry, so that creating it times out,
+// and checks that destroying it cancels the job and waits until it is canceled.
+func TestAccMcmaJob_cancelOnDestroy(t *testing.T) {
+	registry := newDefaultFakeRegistry(mcmatest.AuthTypeMcmaApiKey)
+	defer registry.Close()
+	registry.AddJobEndpoint("StuckFakeJob", "/api/stuck-jobs", nil)
+
+	jobName := acctest.RandomWithPrefix(testAccNamePrefix)
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckJobCanceled(registry, "StuckFakeJob"),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccountMcmaJobCancelOnDestroy(jobName, "StuckFakeJob", fakeJobProfileName, registry.ProviderConfig()),
+				ExpectError: regexp.MustCompile("timeout waiting for job"),
+			},
+		},
+	})
+}
+
+// testAccCheckJobPolled checks that the job of a resource was read from the fake registry more than once.
+func testAccCheckJobPolled(registry *mcmatest.Registry, resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+		jobUrl, err := url.Parse(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if n := registry.CountRequests(http.MethodGet, jobUrl.Path); n < 2 {
+			return fmt.Errorf("expected job %s to be polled until it completed, got %d GET requests", rs.Primary.ID, n)
+		}
+		return nil
+	}
+}
+
+// testAccCheckJobCanceled checks that the only job of the given type in the fake registry was canceled by the
+// provider.
+func testAccCheckJobCanceled(registry *mcmatest.Registry, jobType string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		jobs := registry.Resources(jobType)
+		if len(jobs) != 1 {
+			return fmt.Errorf("expected 1 job of type %s, got %d", jobType, len(jobs))
+		}
+		jobId := jobs[0]["id"].(string)
+		jobUrl, err := url.Parse(jobId)
+		if err != nil {
+			return err
+		}
+		if registry.CountRequests(http.MethodPost, jobUrl.Path+"/cancel") == 0 {
+			return fmt.Errorf("expected job %s to be canceled on destroy", jobId)
+		}
+		if status := jobs[0]["status"]; status != jobStatusCanceled {
+			return fmt.Errorf("expected job %s to have status %s, got %v", jobId, jobStatusCanceled, status)
+		}
+		return nil
+	}
+}
+
+func testAccountMcmaJobCancelOnDestroy(jobName string, jobType string, jobProfileName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job" "job_%s" {
+  job_type          = "%s"
+  job_profile_name  = "%s"
+  job_input         = jsonencode({ inputFile = "s3://bucket/file.mp4" })
+  cancel_on_destroy = true
+
+  timeouts {
+    create = "3s"
+  }
+}
+`, providerConfig, jobName, jobType, jobProfileName)
+}
+
+func testAccountMcmaJob(jobName string, jobType string, jobProfileName string, jobInput string, providerConfig string) string {
+	if jobInput == "" {
+		jobInput = "{}"
+	}
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_job" "job_%s" {
+  job_type         = "%s"
+  job_profile_name = "%s"
+  job_input        = <<EOT
+%s
+EOT
+  triggers = {
+    test = "%s"
+  }
+}
+`, providerConfig, jobName, jobType, jobProfileName, jobInput, jobName)
+}
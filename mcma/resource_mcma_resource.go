@@ -4,9 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
 )
 
 func resourceMcmaResource() *schema.Resource {
@@ -18,6 +26,20 @@ func resourceMcmaResource() *schema.Resource {
 		UpdateContext: resourceMcmaResourceUpdate,
 		DeleteContext: resourceMcmaResourceDelete,
 
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: customdiff.All(
+			customdiff.ComputedIf("date_modified", resourceMcmaResourceChanged),
+			customdiff.ComputedIf("server_json", resourceMcmaResourceChanged),
+			customdiff.ComputedIf("output_values", func(ctx context.Context, d *schema.ResourceDiff, m interface{}) bool {
+				return resourceMcmaResourceChanged(ctx, d, m) || d.HasChange("outputs")
+			}),
+		),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceMcmaResourceImport,
+		},
+
 		Schema: map[string]*schema.Schema{
 			"type": {
 				Type:        schema.TypeString,
@@ -31,14 +53,83 @@ func resourceMcmaResource() *schema.Resource {
 				ForceNew:    true,
 			},
 			"resource_json": {
+				Type:             schema.TypeString,
+				Description:      "The JSON of the object to be created. Differences in key order, whitespace and numeric formatting, and in the properties listed in ignore_fields, do not produce a plan.",
+				Required:         true,
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressEquivalentJsonDiffs,
+			},
+			"ignore_fields": {
+				Type:        schema.TypeList,
+				Description: "JSON paths of properties that are owned by the service, e.g. `metadata.checksum` or `locators.*.url`, which are not read back into resource_json. A leading `$.` is optional and `*` matches every property or array element. `dateCreated` and `dateModified` are always ignored.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateJsonPath,
+				},
+			},
+			"date_created": {
 				Type:        schema.TypeString,
-				Description: "The JSON of the object to be created",
-				Required:    true,
+				Description: "The date and time at which the resource was created.",
+				Computed:    true,
+			},
+			"date_modified": {
+				Type:        schema.TypeString,
+				Description: "The date and time at which the resource was last modified.",
+				Computed:    true,
+			},
+			"known_date_modified": knownDateModifiedSchema(),
+			"server_json": {
+				Type:        schema.TypeString,
+				Description: "The JSON of the resource as returned by the service, including its id, type, dates and any properties set by the service.",
+				Computed:    true,
+			},
+			"outputs": {
+				Type:        schema.TypeMap,
+				Description: "JSON paths of values to extract from the resource as returned by the service, keyed by the name under which they are exposed in output_values, e.g. `status = \"$.status\"`.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateJsonPath,
+				},
+			},
+			"output_values": {
+				Type:        schema.TypeMap,
+				Description: "The values extracted with the JSON paths in outputs. Strings are exposed as they are, numbers and booleans are formatted and objects and arrays are JSON encoded. Values that are not present in the resource are omitted.",
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
 			},
 		},
 	}
 }
 
+func resourceMcmaResourceChanged(_ context.Context, d *schema.ResourceDiff, _ interface{}) bool {
+	return d.HasChange("type") || d.HasChange("resource_json")
+}
+
+// getMcmaResourceOutputValues extracts the values for the JSON paths in outputs from a resource returned by the
+// service.
+func getMcmaResourceOutputValues(resource map[string]interface{}, outputs map[string]interface{}) (map[string]interface{}, error) {
+	outputValues := make(map[string]interface{})
+	for name, path := range outputs {
+		segments, err := parseJsonPath(path.(string))
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %s", name, err)
+		}
+		value, found := getJsonPath(resource, segments)
+		if !found {
+			continue
+		}
+		outputValues[name], err = jsonValueToString(value)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %s", name, err)
+		}
+	}
+	return outputValues, nil
+}
+
 func getMcmaResourceFromResourceData(d *schema.ResourceData) (map[string]interface{}, error) {
 	resourceJson := d.Get("resource_json").(string)
 
@@ -50,34 +141,47 @@ func getMcmaResourceFromResourceData(d *schema.ResourceData) (map[string]interfa
 
 	resourceMap["id"] = d.Id()
 	resourceMap["@type"] = d.Get("type").(string)
+	if dateCreated := d.Get("date_created").(string); dateCreated != "" {
+		resourceMap["dateCreated"] = dateCreated
+	}
+	if dateModified := d.Get("date_modified").(string); dateModified != "" {
+		resourceMap["dateModified"] = dateModified
+	}
 
 	return resourceMap, nil
 }
 
-func resourceMcmaResourceRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	resourceManager, di := getResourceManager(m)
-	if di != nil {
-		return di
+// setMcmaResourceResourceData writes a resource returned by the service to state. The properties listed in
+// ignore_fields are left out of resource_json, but not server_json.
+func setMcmaResourceResourceData(d *schema.ResourceData, resource map[string]interface{}) diag.Diagnostics {
+	resourceType, _ := resource["@type"].(string)
+	resourceId, _ := resource["id"].(string)
+
+	serverJsonBytes, err := json.Marshal(resource)
+	if err != nil {
+		return diag.Errorf("error parsing json for resource of type %s with id %s: %s", resourceType, resourceId, err)
 	}
+	_ = d.Set("server_json", string(serverJsonBytes))
 
-	resourceType := d.Get("type").(string)
-	resourceId := d.Id()
-	resource, err := resourceManager.GetResource(resourceType, resourceId)
+	outputValues, err := getMcmaResourceOutputValues(resource, d.Get("outputs").(map[string]interface{}))
 	if err != nil {
-		return diag.Errorf("error getting resource of type %s with id %s: %s", resourceType, resourceId, err)
+		return diag.Errorf("error getting outputs for resource of type %s with id %s: %s", resourceType, resourceId, err)
 	}
-	if resource == nil {
-		d.SetId("")
-		return diag.Diagnostics{}
+	if err = d.Set("output_values", outputValues); err != nil {
+		return diag.Errorf("error setting output_values for resource of type %s with id %s: %s", resourceType, resourceId, err)
 	}
 
+	dateCreated, _ := resource["dateCreated"].(string)
+	_ = d.Set("date_created", dateCreated)
+	dateModified, _ := resource["dateModified"].(string)
+	_ = d.Set("date_modified", dateModified)
+
 	_ = d.Set("type", resource["@type"])
 	delete(resource, "@type")
-	_ = d.Set("id", resource["id"])
+	d.SetId(resourceId)
 	delete(resource, "id")
 
-	delete(resource, "dateCreated")
-	delete(resource, "dateModified")
+	removeJsonPaths(resource, getIgnoreFields(d))
 
 	jsonBytes, err := json.Marshal(resource)
 	if err != nil {
@@ -88,6 +192,35 @@ func resourceMcmaResourceRead(_ context.Context, d *schema.ResourceData, m inter
 	return diag.Diagnostics{}
 }
 
+func resourceMcmaResourceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return di
+	}
+
+	resourceType := d.Get("type").(string)
+	resourceId := d.Id()
+	get := getMcmaResourceVersion(ctx, resourceManager, resourceType, resourceId)
+	resource, _, err := get()
+	if err == nil && resource == nil && d.IsNewResource() {
+		// a resource that was just created may not be returned by the service straight away
+		resource, err = readAfterWrite(ctx, "resource "+resourceId, time.Time{}, get)
+	}
+	if err != nil {
+		return diag.Errorf("error getting resource of type %s with id %s: %s", resourceType, resourceId, err)
+	}
+	if resource == nil {
+		d.SetId("")
+		return diag.Diagnostics{}
+	}
+
+	if di = setMcmaResourceResourceData(d, resource.(map[string]interface{})); di.HasError() {
+		return di
+	}
+	refreshKnownDateModified(d, resourceMcmaResource())
+	return di
+}
+
 func resourceMcmaResourceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	resourceManager, di := getResourceManager(m)
 	if di != nil {
@@ -99,7 +232,7 @@ func resourceMcmaResourceCreate(ctx context.Context, d *schema.ResourceData, m i
 		return diag.FromErr(err)
 	}
 
-	createdResource, err := resourceManager.Create(resource)
+	createdResource, err := resourceManager.Create(ctx, resource)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -116,29 +249,151 @@ func resourceMcmaResourceUpdate(ctx context.Context, d *schema.ResourceData, m i
 		return di
 	}
 
-	resource, err := getMcmaResourceFromResourceData(d)
-	if err != nil {
-		return diag.FromErr(err)
+	if getConflictDetection(m) {
+		di = checkForConflict(d, resourceMcmaResource(), "resource of type "+d.Get("type").(string), func(remote *schema.ResourceData) diag.Diagnostics {
+			return resourceMcmaResourceRead(ctx, remote, m)
+		})
+		if di != nil {
+			return di
+		}
 	}
 
-	_, err = resourceManager.Update(resource)
-	if err != nil {
-		return diag.FromErr(err)
+	// changes to ignore_fields and outputs only affect what is read back from the service
+	if d.HasChanges("type", "resource_json") {
+		resource, err := getMcmaResourceFromResourceData(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		updatedResource, err := resourceManager.Update(ctx, resource)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		resourceType := d.Get("type").(string)
+		resourceId := d.Id()
+		readResource, err := readAfterWrite(ctx, "resource "+resourceId, getMcmaResourceDateModified(updatedResource), getMcmaResourceVersion(ctx, resourceManager, resourceType, resourceId))
+		if err != nil {
+			return diag.Errorf("error getting resource of type %s with id %s: %s", resourceType, resourceId, err)
+		}
+
+		if di = setMcmaResourceResourceData(d, readResource.(map[string]interface{})); di.HasError() {
+			return di
+		}
+		setKnownDateModified(d)
+		return di
 	}
 
 	return resourceMcmaResourceRead(ctx, d, m)
 }
 
-func resourceMcmaResourceDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceMcmaResourceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	resourceManager, di := getResourceManager(m)
 	if di != nil {
 		return di
 	}
 
-	err := resourceManager.DeleteResource(d.Get("type").(string), d.Id())
+	resourceType := d.Get("type").(string)
+	resourceId := d.Id()
+	err := resourceManager.DeleteResource(ctx, resourceType, resourceId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = waitUntilDeleted(ctx, "resource "+resourceId, getMcmaResourceVersion(ctx, resourceManager, resourceType, resourceId))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diag.Diagnostics{}
 }
+
+// findResourceEndpointForId finds the registered resource endpoint that the resource with the given ID belongs to,
+// along with the auth type used to access it. If more than one endpoint matches, the most specific one is used. If none
+// matches, the returned endpoint is nil.
+func findResourceEndpointForId(ctx context.Context, resourceManager *mcmaclient.ResourceManager, resourceId string) (*mcmamodel.ResourceEndpoint, string, error) {
+	results, err := resourceManager.Query(ctx, reflect.TypeOf(mcmamodel.Service{}), map[string]string{})
+	if err != nil {
+		return nil, "", fmt.Errorf("error querying services: %s", err)
+	}
+
+	var match *mcmamodel.ResourceEndpoint
+	authType := ""
+	matchLength := 0
+	for _, result := range results {
+		service := result.(mcmamodel.Service)
+		for i, resourceEndpoint := range service.Resources {
+			prefix := strings.TrimSuffix(resourceEndpoint.HttpEndpoint, "/") + "/"
+			if strings.HasPrefix(resourceId, prefix) && len(prefix) > matchLength {
+				match = &service.Resources[i]
+				authType = resourceEndpoint.AuthType
+				if authType == "" {
+					authType = service.AuthType
+				}
+				matchLength = len(prefix)
+			}
+		}
+	}
+
+	return match, authType, nil
+}
+
+// getResourceTypeForId finds the type of MCMA resource with the given ID by looking for the registered resource
+// endpoint that the ID belongs to.
+func getResourceTypeForId(ctx context.Context, resourceManager *mcmaclient.ResourceManager, resourceId string) (string, error) {
+	resourceEndpoint, _, err := findResourceEndpointForId(ctx, resourceManager, resourceId)
+	if err != nil {
+		return "", err
+	}
+	if resourceEndpoint == nil {
+		return "", fmt.Errorf("no resource endpoint registered for id %s, import with <type>,<id> instead", resourceId)
+	}
+
+	return resourceEndpoint.ResourceType, nil
+}
+
+// resourceMcmaResourceImport accepts either <type>,<id> or just the ID of the resource, in which case the type is
+// taken from the @type of the document returned by the service that the ID belongs to.
+func resourceMcmaResourceImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return nil, diagsToError(di)
+	}
+
+	resourceType := ""
+	resourceId := d.Id()
+	if parts := strings.SplitN(resourceId, ",", 2); len(parts) == 2 {
+		resourceType = parts[0]
+		resourceId = parts[1]
+	}
+	if !isAbsoluteUrl(resourceId) {
+		return nil, fmt.Errorf("unexpected format of import id %s, expected <type>,<id> or <id> where <id> is an absolute url", d.Id())
+	}
+
+	lookupType := resourceType
+	if lookupType == "" {
+		var err error
+		if lookupType, err = getResourceTypeForId(ctx, resourceManager, resourceId); err != nil {
+			return nil, err
+		}
+	}
+
+	resource, err := resourceManager.GetResource(ctx, lookupType, resourceId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting resource of type %s with id %s: %s", lookupType, resourceId, err)
+	}
+	if resource == nil {
+		return nil, fmt.Errorf("resource of type %s with id %s not found", lookupType, resourceId)
+	}
+
+	if resourceType == "" {
+		if resourceType, _ = resource["@type"].(string); resourceType == "" {
+			resourceType = lookupType
+		}
+	}
+
+	d.SetId(resourceId)
+	_ = d.Set("type", resourceType)
+
+	return []*schema.ResourceData{d}, nil
+}
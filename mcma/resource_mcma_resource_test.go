@@ -1,19 +1,67 @@
 package mcma
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-
-	mcmaclient "github.com/ebu/mcma-libraries-go/client"
 )
 
+func TestGetMcmaResourceOutputValues(t *testing.T) {
+	var resource map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"@type": "BMEssence",
+		"status": "COMPLETED",
+		"duration": 12.5,
+		"archived": false,
+		"metadata": {"name": "essence", "checksum": null},
+		"locators": [{"url": "s3://bucket/a"}, {"url": "s3://bucket/b"}]
+	}`), &resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputValues, err := getMcmaResourceOutputValues(resource, map[string]interface{}{
+		"status":       "$.status",
+		"duration":     "duration",
+		"archived":     "$.archived",
+		"metadata":     "$.metadata",
+		"checksum":     "$.metadata.checksum",
+		"first_url":    "$.locators[0].url",
+		"all_urls":     "$.locators[*].url",
+		"missing":      "$.metadata.description",
+		"out_of_range": "$.locators[2].url",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"status":    "COMPLETED",
+		"duration":  "12.5",
+		"archived":  "false",
+		"metadata":  `{"checksum":null,"name":"essence"}`,
+		"checksum":  "",
+		"first_url": "s3://bucket/a",
+		"all_urls":  `["s3://bucket/a","s3://bucket/b"]`,
+	}
+	if !reflect.DeepEqual(outputValues, expected) {
+		t.Errorf("expected %v, got %v", expected, outputValues)
+	}
+
+	if _, err = getMcmaResourceOutputValues(resource, map[string]interface{}{"invalid": "$."}); err == nil {
+		t.Error("expected error for invalid json path")
+	}
+}
+
 func TestAccMcmaResource_basic(t *testing.T) {
-	resourceName := acctest.RandStringFromCharSet(5, acctest.CharSetAlpha)
+	resourceName := acctest.RandomWithPrefix(testAccNamePrefix)
 	var resourceMap map[string]interface{}
 	createTestCase := func(providerConfig string) resource.TestCase {
 		return resource.TestCase{
@@ -26,8 +74,45 @@ func TestAccMcmaResource_basic(t *testing.T) {
 					Config: testAccountMcmaResource(resourceName, providerConfig),
 					Check: resource.ComposeTestCheckFunc(
 						testAccCheckMcmaResourceExists("mcma_resource.bm_content_"+resourceName, &resourceMap),
+						resource.TestCheckResourceAttrSet("mcma_resource.bm_content_"+resourceName, "date_created"),
+						resource.TestCheckResourceAttrSet("mcma_resource.bm_content_"+resourceName, "date_modified"),
+						resource.TestCheckResourceAttrSet("mcma_resource.bm_content_"+resourceName, "server_json"),
+						resource.TestCheckResourceAttr("mcma_resource.bm_content_"+resourceName, "output_values.name", resourceName),
 					),
 				},
+				{
+					ResourceName:      "mcma_resource.bm_content_" + resourceName,
+					ImportState:       true,
+					ImportStateVerify: true,
+				},
+				{
+					ResourceName:      "mcma_resource.bm_content_" + resourceName,
+					ImportState:       true,
+					ImportStateIdFunc: testAccMcmaResourceImportStateIdWithType("mcma_resource.bm_content_" + resourceName),
+					ImportStateVerify: true,
+				},
+			},
+		}
+	}
+	resource.Test(t, createTestCase(getAwsProfileProviderConfigFromEnvVars()))
+}
+
+func TestAccMcmaResource_semanticJson(t *testing.T) {
+	resourceName := acctest.RandomWithPrefix(testAccNamePrefix)
+	createTestCase := func(providerConfig string) resource.TestCase {
+		return resource.TestCase{
+			Providers: testAccProviders,
+			CheckDestroy: resource.ComposeTestCheckFunc(
+				testAccCheckMcmaResourceDestroy,
+			),
+			Steps: []resource.TestStep{
+				{
+					Config: testAccountMcmaResource(resourceName, providerConfig),
+				},
+				{
+					Config:   testAccountMcmaResourceReformatted(resourceName, providerConfig),
+					PlanOnly: true,
+				},
 			},
 		}
 	}
@@ -35,18 +120,18 @@ func TestAccMcmaResource_basic(t *testing.T) {
 }
 
 func testAccCheckMcmaResourceDestroy(s *terraform.State) error {
-	resourceManager := testAccProvider.Meta().(*mcmaclient.ResourceManager)
+	resourceManager := testAccProvider.Meta().(*providerMeta).resourceManager
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "mcma_resource" {
 			continue
 		}
-		existing, err := resourceManager.GetResource("BMContent", rs.Primary.ID)
+		existing, err := resourceManager.GetResource(context.Background(), "BMContent", rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 		for i := 0; existing != nil && i < 30; i++ {
 			time.Sleep(1 * time.Second)
-			existing, err = resourceManager.GetResource("BMContent", rs.Primary.ID)
+			existing, err = resourceManager.GetResource(context.Background(), "BMContent", rs.Primary.ID)
 			if err != nil {
 				return err
 			}
@@ -64,9 +149,12 @@ func testAccountMcmaResource(resourceName string, providerConfig string) string
 
 resource "mcma_resource" "bm_content_%s" {
   type = "BMContent"
+  outputs = {
+    name = "$.metadata.name"
+  }
   resource_json = jsonencode({
     metadata = {
-      name = "Terraform provider test %s"
+      name = "%s"
       description = "Test asset generated by Terraform provider acceptance tests"
 	}
   })
@@ -74,6 +162,27 @@ resource "mcma_resource" "bm_content_%s" {
 `, providerConfig, resourceName, resourceName)
 }
 
+func testAccountMcmaResourceReformatted(resourceName string, providerConfig string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "mcma_resource" "bm_content_%s" {
+  type = "BMContent"
+  outputs = {
+    name = "$.metadata.name"
+  }
+  resource_json = <<EOT
+{
+  "metadata": {
+    "description": "Test asset generated by Terraform provider acceptance tests",
+    "name":        "%s"
+  }
+}
+EOT
+}
+`, providerConfig, resourceName, resourceName)
+}
+
 func testAccCheckMcmaResourceExists(resourceName string, mcmaResource *map[string]interface{}) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		keys := ""
@@ -90,8 +199,8 @@ func testAccCheckMcmaResourceExists(resourceName string, mcmaResource *map[strin
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("resource ID not set")
 		}
-		resourceManager := testAccProvider.Meta().(*mcmaclient.ResourceManager)
-		p, err := resourceManager.GetResource("BMContent", rs.Primary.ID)
+		resourceManager := testAccProvider.Meta().(*providerMeta).resourceManager
+		p, err := resourceManager.GetResource(context.Background(), "BMContent", rs.Primary.ID)
 		if err != nil {
 			return err
 		}
@@ -102,3 +211,13 @@ func testAccCheckMcmaResourceExists(resourceName string, mcmaResource *map[strin
 		return nil
 	}
 }
+
+func testAccMcmaResourceImportStateIdWithType(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", resourceName)
+		}
+		return rs.Primary.Attributes["type"] + "," + rs.Primary.ID, nil
+	}
+}
@@ -2,16 +2,27 @@ package mcma
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
-	mcmamodel "github.com/ebu/mcma-libraries-go/model"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
 )
 
 func resourceService() *schema.Resource {
+	resourceEndpointSchema := resourceEndpointDataSourceSchema()
+	resourceEndpointSchema["resource_type"].Computed = false
+	resourceEndpointSchema["resource_type"].Required = true
+	resourceEndpointSchema["http_endpoint"].Computed = false
+	resourceEndpointSchema["http_endpoint"].Required = true
+	resourceEndpointSchema["auth_type"].Description = "The type of authentication expected for this endpoint. This should only be specified if it is different than the auth type specified on the service. The provider authenticates requests to the endpoint with the auth block that has this auth type."
+	resourceEndpointSchema["auth_type"].Computed = false
+	resourceEndpointSchema["auth_type"].Optional = true
+
 	return &schema.Resource{
 		Description: "Service data registered in an MCMA Service Registry",
 
@@ -20,6 +31,12 @@ func resourceService() *schema.Resource {
 		UpdateContext: resourceServiceUpdate,
 		DeleteContext: resourceServiceDelete,
 
+		Timeouts: defaultResourceTimeouts(),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServiceImport,
+		},
+
 		Schema: map[string]*schema.Schema{
 			"type": {
 				Type:        schema.TypeString,
@@ -41,6 +58,7 @@ func resourceService() *schema.Resource {
 				Description: "The date and time at which the service data was last modified.",
 				Computed:    true,
 			},
+			"known_date_modified": knownDateModifiedSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Description: "The name of the service",
@@ -48,7 +66,7 @@ func resourceService() *schema.Resource {
 			},
 			"auth_type": {
 				Type:        schema.TypeString,
-				Description: "The type of authentication the service uses, e.g. AWS4",
+				Description: "The type of authentication the service uses, e.g. AWS4. The provider authenticates requests to the service with the auth block that has this auth type.",
 				Optional:    true,
 			},
 			"job_type": {
@@ -60,44 +78,9 @@ func resourceService() *schema.Resource {
 				Type:     schema.TypeSet,
 				Required: true,
 				MinItems: 1,
+				Set:      resourceEndpointHash,
 				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"type": {
-							Type:        schema.TypeString,
-							Description: "The MCMA type of resource. This value will always be 'ResourceEndpoint'.",
-							Computed:    true,
-						},
-						"id": {
-							Type:        schema.TypeString,
-							Description: "The ID of the resource endpoint. MCMA IDs are always absolute urls.",
-							Computed:    true,
-						},
-						"date_created": {
-							Type:        schema.TypeString,
-							Description: "The date and time at which the resource endpoint data was created.",
-							Computed:    true,
-						},
-						"date_modified": {
-							Type:        schema.TypeString,
-							Description: "The date and time at which the resource endpoint data was last modified.",
-							Computed:    true,
-						},
-						"resource_type": {
-							Type:        schema.TypeString,
-							Description: "The type of MCMA resource this endpoint handles.",
-							Required:    true,
-						},
-						"http_endpoint": {
-							Type:        schema.TypeString,
-							Description: "The url for the endpoint.",
-							Required:    true,
-						},
-						"auth_type": {
-							Type:        schema.TypeString,
-							Description: "The type of authentication expected for this endpoint. This should only be specified if it is different than the auth type specified on the service.",
-							Optional:    true,
-						},
-					},
+					Schema: resourceEndpointSchema,
 				},
 			},
 			"job_profile_ids": {
@@ -112,6 +95,30 @@ func resourceService() *schema.Resource {
 	}
 }
 
+// resourceEndpointHash hashes a resource endpoint by the attributes set in config only, so that the hash does not
+// change once the computed attributes are read back from the service registry.
+func resourceEndpointHash(v interface{}) int {
+	resource := v.(map[string]interface{})
+	authType, _ := resource["auth_type"].(string)
+	return schema.HashString(fmt.Sprintf("%s|%s|%s", resource["resource_type"], resource["http_endpoint"], authType))
+}
+
+// parseDate parses a date written to state by formatDate, returning the zero time if it is not set.
+func parseDate(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// formatDate formats a date for state, leaving it empty if the service registry did not return it.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func getServiceFromResourceData(d *schema.ResourceData) mcmamodel.Service {
 	var resources []mcmamodel.ResourceEndpoint
 	for _, r := range d.Get("resource").(*schema.Set).List() {
@@ -119,6 +126,9 @@ func getServiceFromResourceData(d *schema.ResourceData) mcmamodel.Service {
 		authType := resource["auth_type"].(string)
 		resources = append(resources, mcmamodel.ResourceEndpoint{
 			Type:         "ResourceEndpoint",
+			Id:           resource["id"].(string),
+			DateCreated:  parseDate(resource["date_created"].(string)),
+			DateModified: parseDate(resource["date_modified"].(string)),
 			ResourceType: resource["resource_type"].(string),
 			HttpEndpoint: resource["http_endpoint"].(string),
 			AuthType:     authType,
@@ -133,6 +143,9 @@ func getServiceFromResourceData(d *schema.ResourceData) mcmamodel.Service {
 
 	return mcmamodel.Service{
 		Type:          "Service",
+		Id:            d.Id(),
+		DateCreated:   parseDate(d.Get("date_created").(string)),
+		DateModified:  parseDate(d.Get("date_modified").(string)),
 		Name:          d.Get("name").(string),
 		AuthType:      d.Get("auth_type").(string),
 		JobType:       d.Get("job_type").(string),
@@ -141,14 +154,64 @@ func getServiceFromResourceData(d *schema.ResourceData) mcmamodel.Service {
 	}
 }
 
-func resourceServiceRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func flattenServiceResources(service mcmamodel.Service) []map[string]interface{} {
+	var resources []map[string]interface{}
+	for _, resourceEndpoint := range service.Resources {
+		r := make(map[string]interface{})
+		r["type"] = "ResourceEndpoint"
+		r["id"] = resourceEndpoint.Id
+		r["date_created"] = formatDate(resourceEndpoint.DateCreated)
+		r["date_modified"] = formatDate(resourceEndpoint.DateModified)
+		r["resource_type"] = resourceEndpoint.ResourceType
+		r["http_endpoint"] = resourceEndpoint.HttpEndpoint
+		r["auth_type"] = resourceEndpoint.AuthType
+		resources = append(resources, r)
+	}
+	return resources
+}
+
+// setServiceResourceData writes every attribute of a service returned by the service registry to state.
+func setServiceResourceData(d *schema.ResourceData, service mcmamodel.Service) diag.Diagnostics {
+	d.SetId(service.Id)
+	_ = d.Set("type", service.Type)
+	_ = d.Set("date_created", formatDate(service.DateCreated))
+	_ = d.Set("date_modified", formatDate(service.DateModified))
+	_ = d.Set("name", service.Name)
+	_ = d.Set("auth_type", service.AuthType)
+	_ = d.Set("job_type", service.JobType)
+	_ = d.Set("job_profile_ids", service.JobProfileIds)
+
+	if err := d.Set("resource", flattenServiceResources(service)); err != nil {
+		return diag.Errorf("error setting resources for service with id %s: %s", service.Id, err)
+	}
+
+	return diag.Diagnostics{}
+}
+
+// getServiceVersion returns a versionGetter for the service with the given ID.
+func getServiceVersion(ctx context.Context, resourceManager *mcmaclient.ResourceManager, serviceId string) versionGetter {
+	return func() (interface{}, time.Time, error) {
+		resource, err := resourceManager.Get(ctx, reflect.TypeOf(mcmamodel.Service{}), serviceId)
+		if err != nil || resource == nil {
+			return nil, time.Time{}, err
+		}
+		return resource, resource.(mcmamodel.Service).DateModified, nil
+	}
+}
+
+func resourceServiceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	resourceManager, di := getResourceManager(m)
 	if di != nil {
 		return di
 	}
 
 	serviceId := d.Id()
-	resource, err := resourceManager.Get(reflect.TypeOf(mcmamodel.Service{}), serviceId)
+	get := getServiceVersion(ctx, resourceManager, serviceId)
+	resource, _, err := get()
+	if err == nil && resource == nil && d.IsNewResource() {
+		// a service that was just created may not be returned by the service registry straight away
+		resource, err = readAfterWrite(ctx, "service "+serviceId, time.Time{}, get)
+	}
 	if err != nil {
 		return diag.Errorf("error getting service with id %s: %s", serviceId, err)
 	}
@@ -157,30 +220,11 @@ func resourceServiceRead(_ context.Context, d *schema.ResourceData, m interface{
 		return diag.Diagnostics{}
 	}
 
-	service := resource.(mcmamodel.Service)
-
-	_ = d.Set("id", service.Id)
-	_ = d.Set("type", service.Type)
-	_ = d.Set("date_created", service.DateCreated.Format(time.RFC3339))
-	_ = d.Set("date_modified", service.DateModified.Format(time.RFC3339))
-	_ = d.Set("name", service.Name)
-	_ = d.Set("auth_type", service.AuthType)
-	_ = d.Set("job_profile_ids", service.JobProfileIds)
-
-	var resources []map[string]interface{}
-	for _, resourceEndpoint := range service.Resources {
-		r := make(map[string]interface{})
-		r["type"] = "ResourceEndpoint"
-		r["resource_type"] = resourceEndpoint.ResourceType
-		r["http_endpoint"] = resourceEndpoint.HttpEndpoint
-		r["auth_type"] = resourceEndpoint.AuthType
-		resources = append(resources, r)
-	}
-	if err = d.Set("resource", resources); err != nil {
-		return diag.Errorf("error setting resources for service with id %s: %s", serviceId, err)
+	if di = setServiceResourceData(d, resource.(mcmamodel.Service)); di.HasError() {
+		return di
 	}
-
-	return diag.Diagnostics{}
+	refreshKnownDateModified(d, resourceService())
+	return di
 }
 
 func resourceServiceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -190,7 +234,7 @@ func resourceServiceCreate(ctx context.Context, d *schema.ResourceData, m interf
 	}
 
 	service := getServiceFromResourceData(d)
-	createdResource, err := resourceManager.Create(service)
+	createdResource, err := resourceManager.Create(ctx, service)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -207,33 +251,85 @@ func resourceServiceUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		return di
 	}
 
-	service := getServiceFromResourceData(d)
-	service.Id = d.Id()
+	if getConflictDetection(m) {
+		di = checkForConflict(d, resourceService(), "service", func(remote *schema.ResourceData) diag.Diagnostics {
+			return resourceServiceRead(ctx, remote, m)
+		})
+		if di != nil {
+			return di
+		}
+	}
 
-	if dc, err := time.Parse(time.RFC3339, d.Get("date_created").(string)); err == nil {
-		service.DateCreated = dc
-	} else {
+	service := getServiceFromResourceData(d)
+	if service.DateCreated.IsZero() {
 		service.DateCreated = time.Now().UTC()
 	}
 
-	_, err := resourceManager.Update(service)
+	updatedResource, err := resourceManager.Update(ctx, service)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceServiceRead(ctx, d, m)
+	// without the date returned by the update, any version of the service is accepted as the one written
+	var updatedDateModified time.Time
+	if updatedService, ok := updatedResource.(mcmamodel.Service); ok {
+		updatedDateModified = updatedService.DateModified
+	}
+
+	serviceId := d.Id()
+	resource, err := readAfterWrite(ctx, "service "+serviceId, updatedDateModified, getServiceVersion(ctx, resourceManager, serviceId))
+	if err != nil {
+		return diag.Errorf("error getting service with id %s: %s", serviceId, err)
+	}
+
+	if di = setServiceResourceData(d, resource.(mcmamodel.Service)); di.HasError() {
+		return di
+	}
+	setKnownDateModified(d)
+	return di
 }
 
-func resourceServiceDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceServiceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	resourceManager, di := getResourceManager(m)
 	if di != nil {
 		return di
 	}
 
-	err := resourceManager.Delete(reflect.TypeOf(mcmamodel.Service{}), d.Id())
+	serviceId := d.Id()
+	err := resourceManager.Delete(ctx, reflect.TypeOf(mcmamodel.Service{}), serviceId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = waitUntilDeleted(ctx, "service "+serviceId, getServiceVersion(ctx, resourceManager, serviceId))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	return diag.Diagnostics{}
 }
+
+// resourceServiceImport accepts either the ID of a service or its name, in which case the ID is resolved through the
+// service registry.
+func resourceServiceImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if isAbsoluteUrl(d.Id()) {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	resourceManager, di := getResourceManager(m)
+	if di != nil {
+		return nil, diagsToError(di)
+	}
+
+	name := d.Id()
+	service, di := getServiceByName(ctx, resourceManager, name)
+	if di != nil {
+		return nil, diagsToError(di)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service with name %s not found", name)
+	}
+
+	d.SetId(service.Id)
+	return []*schema.ResourceData{d}, nil
+}
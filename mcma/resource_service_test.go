@@ -1,7 +1,9 @@
 package mcma
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
@@ -10,13 +12,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
-	mcmaclient "github.com/ebu/mcma-libraries-go/client"
-	mcmamodel "github.com/ebu/mcma-libraries-go/model"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+
+	"github.com/ebu/terraform-provider-mcma/mcmatest"
 )
 
 func TestAccMcmaService_basic(t *testing.T) {
 	var service mcmamodel.Service
-	profileName := acctest.RandStringFromCharSet(5, acctest.CharSetAlpha)
+	profileName := acctest.RandomWithPrefix(testAccNamePrefix)
 	createTestCase := func(providerConfig string) resource.TestCase {
 		return resource.TestCase{
 			Providers: testAccProviders,
@@ -36,6 +39,17 @@ func TestAccMcmaService_basic(t *testing.T) {
 						testAccCheckServiceExists("mcma_service.service_"+profileName, &service),
 					),
 				},
+				{
+					ResourceName:      "mcma_service.service_" + profileName,
+					ImportState:       true,
+					ImportStateVerify: true,
+				},
+				{
+					ResourceName:      "mcma_service.service_" + profileName,
+					ImportState:       true,
+					ImportStateId:     profileName,
+					ImportStateVerify: true,
+				},
 			},
 		}
 	}
@@ -44,19 +58,58 @@ func TestAccMcmaService_basic(t *testing.T) {
 	resource.Test(t, createTestCase(getMcmaApiKeyProviderConfigFromEnvVars()))
 }
 
+// TestAccMcmaService_eventualConsistency runs against a fake registry whose writes take a while to become visible and
+// that fails some requests, which the provider must wait for and retry.
+func TestAccMcmaService_eventualConsistency(t *testing.T) {
+	registry := newDefaultFakeRegistry(mcmatest.AuthTypeMcmaApiKey)
+	defer registry.Close()
+	registry.SetConsistencyDelay(2 * time.Second)
+	registry.SetLatency(10 * time.Millisecond)
+
+	serviceName := acctest.RandomWithPrefix(testAccNamePrefix)
+	providerConfig := getMcmaApiKeyProviderConfig(registry.URL(), mcmatest.McmaApiKey)
+	var service mcmamodel.Service
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckMcmaServiceDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					registry.FailNext(http.StatusServiceUnavailable)
+				},
+				Config: testAccountMcmaService(serviceName, providerConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceExists("mcma_service.service_"+serviceName, &service),
+				),
+			},
+			{
+				Config: testAccountMcmaServiceUpdatedEndpoint(serviceName, providerConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServiceExists("mcma_service.service_"+serviceName, &service),
+					resource.TestCheckTypeSetElemNestedAttrs("mcma_service.service_"+serviceName, "resource.*", map[string]string{
+						"http_endpoint": "https://some.updated-endpoint.com/api/job-assignments",
+					}),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckMcmaServiceDestroy(s *terraform.State) error {
-	resourceManager := testAccProvider.Meta().(*mcmaclient.ResourceManager)
+	resourceManager := testAccProvider.Meta().(*providerMeta).resourceManager
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "mcma_service" {
 			continue
 		}
-		existing, err := resourceManager.Get(reflect.TypeOf(mcmamodel.Service{}), rs.Primary.ID)
+		existing, err := resourceManager.Get(context.Background(), reflect.TypeOf(mcmamodel.Service{}), rs.Primary.ID)
 		if err != nil {
 			return err
 		}
 		for i := 0; existing != nil && i < 30; i++ {
 			time.Sleep(1 * time.Second)
-			existing, err = resourceManager.Get(reflect.TypeOf(mcmamodel.Service{}), rs.Primary.ID)
+			existing, err = resourceManager.Get(context.Background(), reflect.TypeOf(mcmamodel.Service{}), rs.Primary.ID)
 			if err != nil {
 				return err
 			}
@@ -127,8 +180,8 @@ func testAccCheckServiceExists(resourceName string, service *mcmamodel.Service)
 		if rs.Primary.ID == "" {
 			return fmt.Errorf("service ID not set")
 		}
-		resourceManager := testAccProvider.Meta().(*mcmaclient.ResourceManager)
-		p, err := resourceManager.Get(reflect.TypeOf(mcmamodel.Service{}), rs.Primary.ID)
+		resourceManager := testAccProvider.Meta().(*providerMeta).resourceManager
+		p, err := resourceManager.Get(context.Background(), reflect.TypeOf(mcmamodel.Service{}), rs.Primary.ID)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,272 @@
+package mcma
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	defaultRetryMaxAttempts = 4
+	defaultRetryMinBackoff  = "1s"
+	defaultRetryMaxBackoff  = "30s"
+)
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func retryResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"max_attempts": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum number of attempts for a request, including the first one. Set to 1 to disable retries.",
+				Optional:     true,
+				Default:      defaultRetryMaxAttempts,
+				ValidateFunc: validateIntAtLeast(1),
+			},
+			"min_backoff": {
+				Type:             schema.TypeString,
+				Description:      "The time to wait before the first retry, e.g. '500ms'. The wait time doubles with every retry.",
+				Optional:         true,
+				Default:          defaultRetryMinBackoff,
+				ValidateDiagFunc: validateDuration,
+			},
+			"max_backoff": {
+				Type:             schema.TypeString,
+				Description:      "The maximum time to wait between retries, e.g. '30s'. A longer wait requested by the server in a Retry-After header is honoured, unless it would exceed the timeout of the Terraform operation, in which case the request fails without being retried.",
+				Optional:         true,
+				Default:          defaultRetryMaxBackoff,
+				ValidateDiagFunc: validateDuration,
+			},
+			"retryable_status_codes": {
+				Type:        schema.TypeSet,
+				Description: "The HTTP status codes for which a request is retried. Defaults to 429, 502, 503 and 504.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+		},
+	}
+}
+
+func validateIntAtLeast(min int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) ([]string, []error) {
+		if v.(int) < min {
+			return nil, []error{fmt.Errorf("%s must be at least %d, got %d", k, min, v.(int))}
+		}
+		return nil, nil
+	}
+}
+
+type retryPolicy struct {
+	maxAttempts          int
+	minBackoff           time.Duration
+	maxBackoff           time.Duration
+	retryableStatusCodes map[int]bool
+}
+
+func getRetryPolicy(d *schema.ResourceData) (retryPolicy, diag.Diagnostics) {
+	retryData := map[string]interface{}{}
+	if blocks := d.Get("retry").([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+		retryData = blocks[0].(map[string]interface{})
+	}
+
+	policy := retryPolicy{
+		maxAttempts:          defaultRetryMaxAttempts,
+		retryableStatusCodes: make(map[int]bool),
+	}
+	if maxAttempts, ok := retryData["max_attempts"].(int); ok {
+		policy.maxAttempts = maxAttempts
+	}
+
+	minBackoff, ok := retryData["min_backoff"].(string)
+	if !ok {
+		minBackoff = defaultRetryMinBackoff
+	}
+	maxBackoff, ok := retryData["max_backoff"].(string)
+	if !ok {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	var err error
+	if policy.minBackoff, err = time.ParseDuration(minBackoff); err != nil {
+		return policy, diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid min_backoff.",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("retry").IndexInt(0).GetAttr("min_backoff"),
+			},
+		}
+	}
+	if policy.maxBackoff, err = time.ParseDuration(maxBackoff); err != nil {
+		return policy, diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid max_backoff.",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("retry").IndexInt(0).GetAttr("max_backoff"),
+			},
+		}
+	}
+	if policy.maxBackoff < policy.minBackoff {
+		return policy, diag.Diagnostics{
+			diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid max_backoff.",
+				Detail:        "max_backoff must not be less than min_backoff.",
+				AttributePath: cty.GetAttrPath("retry").IndexInt(0).GetAttr("max_backoff"),
+			},
+		}
+	}
+
+	if statusCodes, ok := retryData["retryable_status_codes"].(*schema.Set); ok && statusCodes.Len() > 0 {
+		for _, statusCode := range statusCodes.List() {
+			policy.retryableStatusCodes[statusCode.(int)] = true
+		}
+	} else {
+		for _, statusCode := range defaultRetryableStatusCodes {
+			policy.retryableStatusCodes[statusCode] = true
+		}
+	}
+
+	return policy, nil
+}
+
+// backoff returns the time to wait before the given retry (starting at 1), doubling the minimum backoff for
+// every retry up to the maximum backoff. Half of the wait time is randomized so that parallel requests that
+// were throttled together do not retry together.
+func (p retryPolicy) backoff(retry int) time.Duration {
+	backoff := p.minBackoff
+	for i := 1; i < retry && backoff < p.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.maxBackoff {
+		backoff = p.maxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// retryTransport retries requests that fail with a retryable status code, honouring the Retry-After header
+// if the server sends one, even beyond the maximum backoff. A request is not retried if the wait would outlast the
+// deadline of its context, i.e. the timeout of the Terraform operation, so that the failure is reported instead of
+// a context error. Requests that fail without a response or with a gateway error are only retried for idempotent
+// methods, as the server may have processed them. Other requests, such as submitting a job, are only retried when
+// the server says it did not process them, see isRetryableWithoutIdempotency.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy retryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.policy.maxAttempts || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return resp, err
+		}
+
+		ctx := req.Context()
+		logFields := map[string]interface{}{
+			"method":       req.Method,
+			"url":          req.URL.String(),
+			"attempt":      attempt,
+			"max_attempts": t.policy.maxAttempts,
+		}
+		wait := t.policy.backoff(attempt)
+		if err != nil {
+			if !isIdempotent(req.Method) {
+				return resp, err
+			}
+			logFields["error"] = err.Error()
+		} else {
+			retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			if !t.policy.retryableStatusCodes[resp.StatusCode] ||
+				(!isIdempotent(req.Method) && !isRetryableWithoutIdempotency(resp.StatusCode, hasRetryAfter)) {
+				return resp, err
+			}
+			if hasRetryAfter {
+				wait = retryAfter
+			}
+			logFields["status_code"] = resp.StatusCode
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		logFields["wait"] = wait.String()
+		tflog.Warn(ctx, "Retrying MCMA request", logFields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableWithoutIdempotency returns if a request that is not idempotent can be retried after failing with the
+// given status code. Gateways send 502 and 504 after the backend may already have accepted the request, so only
+// throttling, and unavailability that comes with a Retry-After header, are known to have left the request unprocessed.
+func isRetryableWithoutIdempotency(statusCode int, hasRetryAfter bool) bool {
+	return statusCode == http.StatusTooManyRequests || (statusCode == http.StatusServiceUnavailable && hasRetryAfter)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
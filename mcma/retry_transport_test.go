@@ -0,0 +1,271 @@
+package mcma
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRetryTransport(maxAttempts int) *retryTransport {
+	return &retryTransport{
+		next: http.DefaultTransport,
+		policy: retryPolicy{
+			maxAttempts: maxAttempts,
+			minBackoff:  time.Millisecond,
+			maxBackoff:  10 * time.Millisecond,
+			retryableStatusCodes: map[int]bool{
+				http.StatusTooManyRequests:    true,
+				http.StatusServiceUnavailable: true,
+			},
+		},
+	}
+}
+
+func TestRetryTransport_retriesRetryableStatusCodes(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		switch len(bodies) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(4)}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"name":"test"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != `{"name":"test"}` {
+			t.Errorf("expected request body to be sent again on attempt %d, got '%s'", i+1, body)
+		}
+	}
+}
+
+func TestRetryTransport_nonIdempotentRequests(t *testing.T) {
+	cases := []struct {
+		method     string
+		statusCode int
+		retryAfter string
+		attempts   int
+	}{
+		{http.MethodPost, http.StatusTooManyRequests, "", 2},
+		{http.MethodPost, http.StatusServiceUnavailable, "0", 2},
+		{http.MethodPost, http.StatusServiceUnavailable, "", 1},
+		{http.MethodPost, http.StatusBadGateway, "", 1},
+		{http.MethodPost, http.StatusGatewayTimeout, "0", 1},
+		{http.MethodPut, http.StatusBadGateway, "", 2},
+		{http.MethodPut, http.StatusServiceUnavailable, "", 2},
+	}
+	for _, c := range cases {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				if c.retryAfter != "" {
+					w.Header().Set("Retry-After", c.retryAfter)
+				}
+				w.WriteHeader(c.statusCode)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		transport := newTestRetryTransport(2)
+		transport.policy.retryableStatusCodes[http.StatusBadGateway] = true
+		transport.policy.retryableStatusCodes[http.StatusGatewayTimeout] = true
+		req, _ := http.NewRequest(c.method, server.URL, strings.NewReader("{}"))
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		_ = resp.Body.Close()
+		server.Close()
+
+		if attempts != c.attempts {
+			t.Errorf("%s with status %d and Retry-After '%s': expected %d attempts, got %d", c.method, c.statusCode, c.retryAfter, c.attempts, attempts)
+		}
+	}
+}
+
+func TestRetryTransport_retryAfterBeyondMaxBackoff(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(2)}
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_ = resp.Body.Close()
+
+	if elapsed := time.Since(start); attempts != 2 || elapsed < time.Second {
+		t.Fatalf("expected request to be retried after the Retry-After wait, got %d attempts after %s", attempts, elapsed)
+	}
+}
+
+func TestRetryTransport_retryAfterBeyondDeadline(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	start := time.Now()
+	resp, err := (&http.Client{Transport: newTestRetryTransport(2)}).Do(req)
+	if err != nil {
+		t.Fatalf("expected the throttled response instead of an error, got %s", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d", http.StatusTooManyRequests, resp.StatusCode)
+	}
+	if elapsed := time.Since(start); attempts != 1 || elapsed > time.Second {
+		t.Fatalf("expected request not to be retried when Retry-After exceeds the deadline, got %d attempts after %s", attempts, elapsed)
+	}
+}
+
+func TestRetryTransport_stopsAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(3)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_doesNotRetryOtherStatusCodes(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(3)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_ = resp.Body.Close()
+
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_connectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	attempts := 0
+	transport := newTestRetryTransport(3)
+	transport.next = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := &http.Client{Transport: transport}
+
+	if _, err := client.Get(url); err == nil {
+		t.Fatal("expected error calling closed server")
+	}
+	if attempts != 3 {
+		t.Fatalf("expected GET to be attempted 3 times, got %d", attempts)
+	}
+
+	attempts = 0
+	if _, err := client.Post(url, "application/json", strings.NewReader("{}")); err == nil {
+		t.Fatal("expected error calling closed server")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected POST to be attempted once, got %d", attempts)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("5"); !ok || wait != 5*time.Second {
+		t.Errorf("expected 5s, got %s", wait)
+	}
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date); !ok || wait <= 5*time.Second || wait > 10*time.Second {
+		t.Errorf("expected about 10s, got %s", wait)
+	}
+	if _, ok := parseRetryAfter(""); ok {
+		t.Error("expected empty value not to be parsed")
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("expected invalid value not to be parsed")
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := retryPolicy{
+		minBackoff: time.Second,
+		maxBackoff: 5 * time.Second,
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, max := range expected {
+		backoff := policy.backoff(i + 1)
+		if backoff < max/2 || backoff > max {
+			t.Errorf("expected backoff for retry %d to be between %s and %s, got %s", i+1, max/2, max, backoff)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package mcma
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+)
+
+// roundTripTestCase describes a resource whose state must survive being built into an MCMA document, serialized as
+// it would be sent to the service registry, and flattened back into state.
+type roundTripTestCase struct {
+	name     string
+	resource *schema.Resource
+	// config is the raw configuration of the attributes that are not stored in the document, such as settings for how
+	// the provider reads it back. Everything else must come from the document.
+	config map[string]interface{}
+	// document is the JSON of the resource as returned by the service registry
+	document string
+	// build converts the state of the resource into the document sent to the service registry
+	build func(d *schema.ResourceData) (interface{}, error)
+	// flatten decodes a document returned by the service registry and writes it to state
+	flatten func(d *schema.ResourceData, document []byte) diag.Diagnostics
+	// unset lists the attributes, or whole blocks, that are not expected to be set in state by this test case
+	unset []string
+}
+
+func testRoundTrip(t *testing.T, c roundTripTestCase) {
+	t.Helper()
+
+	d1 := schema.TestResourceDataRaw(t, c.resource.Schema, c.config)
+	if di := c.flatten(d1, []byte(c.document)); di.HasError() {
+		t.Fatalf("error flattening document: %v", di)
+	}
+
+	built, err := c.build(d1)
+	if err != nil {
+		t.Fatalf("error building document from state: %s", err)
+	}
+	serialized, err := json.Marshal(built)
+	if err != nil {
+		t.Fatalf("error serializing document: %s", err)
+	}
+
+	d2 := schema.TestResourceDataRaw(t, c.resource.Schema, c.config)
+	if di := c.flatten(d2, serialized); di.HasError() {
+		t.Fatalf("error flattening serialized document: %v", di)
+	}
+
+	state1 := d1.State().Attributes
+	state2 := d2.State().Attributes
+	if !reflect.DeepEqual(state1, state2) {
+		for k, v := range state1 {
+			if state2[k] != v {
+				t.Errorf("attribute %s: expected %q after round trip, got %q", k, v, state2[k])
+			}
+		}
+		for k, v := range state2 {
+			if _, found := state1[k]; !found {
+				t.Errorf("attribute %s: unexpected value %q after round trip", k, v)
+			}
+		}
+	}
+
+	// every attribute in the schema, including those of nested blocks, should have been read back from the document,
+	// except known_date_modified that is set by the CRUD functions
+	unset := map[string]bool{"known_date_modified": true}
+	for _, path := range c.unset {
+		unset[path] = true
+	}
+	for _, path := range schemaAttributePaths("", c.resource.Schema) {
+		if unset[path] || unset[strings.SplitN(path, ".", 2)[0]] {
+			continue
+		}
+		pattern := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(path), `\*`, `[^.]+`) + `(\.[^#%]+)?$`)
+		found := false
+		for attribute, value := range state1 {
+			if pattern.MatchString(attribute) && value != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("attribute %s was not read back from the document", path)
+		}
+	}
+}
+
+// schemaAttributePaths returns the paths of all attributes in a schema, using * for the index of nested blocks.
+func schemaAttributePaths(prefix string, s map[string]*schema.Schema) []string {
+	var paths []string
+	for k, v := range s {
+		if r, ok := v.Elem.(*schema.Resource); ok {
+			paths = append(paths, schemaAttributePaths(prefix+k+".*.", r.Schema)...)
+		} else {
+			paths = append(paths, prefix+k)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := []roundTripTestCase{
+		{
+			name:     "mcma_service",
+			resource: resourceService(),
+			document: `{
+				"@type": "Service",
+				"id": "https://service-registry.mcma.io/api/services/1",
+				"dateCreated": "2022-03-01T10:00:00Z",
+				"dateModified": "2022-03-02T11:30:00Z",
+				"name": "MediaInfo AME Service",
+				"authType": "AWS4",
+				"jobType": "AmeJob",
+				"resources": [
+					{
+						"@type": "ResourceEndpoint",
+						"id": "https://service-registry.mcma.io/api/services/1/resources/1",
+						"dateCreated": "2022-03-01T10:00:00Z",
+						"dateModified": "2022-03-02T11:30:00Z",
+						"resourceType": "JobAssignment",
+						"httpEndpoint": "https://service.mcma.io/api/job-assignments"
+					},
+					{
+						"@type": "ResourceEndpoint",
+						"id": "https://service-registry.mcma.io/api/services/1/resources/2",
+						"dateCreated": "2022-03-01T10:00:00Z",
+						"dateModified": "2022-03-02T11:30:00Z",
+						"resourceType": "JobAssignment",
+						"httpEndpoint": "https://service.mcma.io/api/job-assignments",
+						"authType": "JWT"
+					}
+				],
+				"jobProfileIds": [
+					"https://service-registry.mcma.io/api/job-profiles/1",
+					"https://service-registry.mcma.io/api/job-profiles/2"
+				]
+			}`,
+			build: func(d *schema.ResourceData) (interface{}, error) {
+				return getServiceFromResourceData(d), nil
+			},
+			flatten: func(d *schema.ResourceData, document []byte) diag.Diagnostics {
+				var service mcmamodel.Service
+				if err := json.Unmarshal(document, &service); err != nil {
+					return diag.FromErr(err)
+				}
+				return setServiceResourceData(d, service)
+			},
+		},
+		{
+			name:     "mcma_job_profile",
+			resource: resourceJobProfile(),
+			document: `{
+				"@type": "JobProfile",
+				"id": "https://service-registry.mcma.io/api/job-profiles/1",
+				"dateCreated": "2022-03-01T10:00:00Z",
+				"dateModified": "2022-03-02T11:30:00Z",
+				"name": "ExtractTechnicalMetadata",
+				"inputParameters": [
+					{"parameterName": "inputFile", "parameterType": "Locator", "description": "The file to analyze"},
+					{"parameterName": "format", "parameterType": "string", "allowedValues": ["json", "xml"], "defaultValue": "json"}
+				],
+				"optionalInputParameters": [{"parameterName": "outputLocation", "parameterType": "Locator"}],
+				"outputParameters": [{"parameterName": "outputFile", "parameterType": "Locator", "description": "The analysis report"}],
+				"custom": {"tier": "premium"}
+			}`,
+			build: func(d *schema.ResourceData) (interface{}, error) {
+				return getJobProfileFromResourceData(d)
+			},
+			flatten: func(d *schema.ResourceData, document []byte) diag.Diagnostics {
+				var jobProfile jobProfileDocument
+				if err := json.Unmarshal(document, &jobProfile); err != nil {
+					return diag.FromErr(err)
+				}
+				return setJobProfileResourceData(d, jobProfile)
+			},
+			unset: []string{"custom_properties_json", "ordered_input_parameter", "ordered_output_parameter"},
+		},
+		{
+			name:     "mcma_job_profile with ordered parameters",
+			resource: resourceJobProfile(),
+			config: map[string]interface{}{
+				"ordered_input_parameter": []interface{}{
+					map[string]interface{}{"name": "inputFile", "type": "Locator"},
+					map[string]interface{}{"name": "format", "type": "string"},
+					map[string]interface{}{"name": "outputLocation", "type": "Locator", "optional": true},
+				},
+				"ordered_output_parameter": []interface{}{
+					map[string]interface{}{"name": "outputFile", "type": "Locator"},
+				},
+			},
+			document: `{
+				"@type": "JobProfile",
+				"id": "https://service-registry.mcma.io/api/job-profiles/1",
+				"dateCreated": "2022-03-01T10:00:00Z",
+				"dateModified": "2022-03-02T11:30:00Z",
+				"name": "ExtractTechnicalMetadata",
+				"inputParameters": [
+					{"parameterName": "inputFile", "parameterType": "Locator", "description": "The file to analyze"},
+					{"parameterName": "format", "parameterType": "string", "allowedValues": ["json", "xml"], "defaultValue": "json"}
+				],
+				"optionalInputParameters": [{"parameterName": "outputLocation", "parameterType": "Locator"}],
+				"outputParameters": [{"parameterName": "outputFile", "parameterType": "Locator", "description": "The analysis report"}],
+				"custom": {"tier": "premium"}
+			}`,
+			build: func(d *schema.ResourceData) (interface{}, error) {
+				return getJobProfileFromResourceData(d)
+			},
+			flatten: func(d *schema.ResourceData, document []byte) diag.Diagnostics {
+				var jobProfile jobProfileDocument
+				if err := json.Unmarshal(document, &jobProfile); err != nil {
+					return diag.FromErr(err)
+				}
+				return setJobProfileResourceData(d, jobProfile)
+			},
+			unset: []string{"custom_properties_json", "input_parameter", "output_parameter"},
+		},
+		{
+			name:     "mcma_job_profile with custom_properties_json",
+			resource: resourceJobProfile(),
+			config: map[string]interface{}{
+				"custom_properties_json": "{}",
+			},
+			document: `{
+				"@type": "JobProfile",
+				"id": "https://service-registry.mcma.io/api/job-profiles/1",
+				"dateCreated": "2022-03-01T10:00:00Z",
+				"dateModified": "2022-03-02T11:30:00Z",
+				"name": "ExtractTechnicalMetadata",
+				"inputParameters": [{"parameterName": "inputFile", "parameterType": "Locator"}],
+				"optionalInputParameters": [{"parameterName": "outputLocation", "parameterType": "Locator"}],
+				"outputParameters": [{"parameterName": "outputFile", "parameterType": "Locator"}],
+				"custom": {"tier": "premium", "priority": 2, "gpu": true, "limits": {"maxDuration": 3600}}
+			}`,
+			build: func(d *schema.ResourceData) (interface{}, error) {
+				return getJobProfileFromResourceData(d)
+			},
+			flatten: func(d *schema.ResourceData, document []byte) diag.Diagnostics {
+				var jobProfile jobProfileDocument
+				if err := json.Unmarshal(document, &jobProfile); err != nil {
+					return diag.FromErr(err)
+				}
+				return setJobProfileResourceData(d, jobProfile)
+			},
+			unset: []string{
+				"custom_properties",
+				"ordered_input_parameter",
+				"ordered_output_parameter",
+				"input_parameter.*.allowed_values",
+				"input_parameter.*.default_value",
+				"input_parameter.*.description",
+				"output_parameter.*.description",
+			},
+		},
+		{
+			name:     "mcma_resource",
+			resource: resourceMcmaResource(),
+			config: map[string]interface{}{
+				"ignore_fields": []interface{}{"$.checksum"},
+				"outputs": map[string]interface{}{
+					"name": "$.metadata.name",
+				},
+			},
+			document: `{
+				"@type": "BMContent",
+				"id": "https://service.mcma.io/api/bm-contents/1",
+				"dateCreated": "2022-03-01T10:00:00Z",
+				"dateModified": "2022-03-02T11:30:00Z",
+				"metadata": {"name": "Test asset"},
+				"status": "NEW"
+			}`,
+			build: func(d *schema.ResourceData) (interface{}, error) {
+				return getMcmaResourceFromResourceData(d)
+			},
+			flatten: func(d *schema.ResourceData, document []byte) diag.Diagnostics {
+				resource := make(map[string]interface{})
+				if err := json.Unmarshal(document, &resource); err != nil {
+					return diag.FromErr(err)
+				}
+				return setMcmaResourceResourceData(d, resource)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			testRoundTrip(t, c)
+		})
+	}
+}
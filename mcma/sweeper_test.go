@@ -0,0 +1,242 @@
+package mcma
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/ebu/terraform-provider-mcma/internal/mcmaclient"
+	"github.com/ebu/terraform-provider-mcma/internal/mcmamodel"
+
+	"github.com/ebu/terraform-provider-mcma/mcmatest"
+)
+
+// testAccNamePrefix starts the names of the objects created by the acceptance tests, so that the sweepers can find
+// them.
+const testAccNamePrefix = "tf-acc-test"
+
+// The sweepers delete the objects that failed acceptance test runs leave behind in the registries set in
+// MCMA_AWS_SERVICE_REGISTRY_URL and MCMA_API_KEY_SERVICE_REGISTRY_URL, i.e. those whose name starts with
+// testAccNamePrefix. They run with:
+//
+//	go test ./mcma -v -sweep=all
+//
+// MCMA resources are only swept for the resource types in MCMA_SWEEP_RESOURCE_TYPES, a comma separated list that
+// defaults to the BMContent type used by the acceptance tests.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("mcma_service", &resource.Sweeper{
+		Name: "mcma_service",
+		F:    sweepServices,
+	})
+	resource.AddTestSweepers("mcma_job_profile", &resource.Sweeper{
+		Name:         "mcma_job_profile",
+		Dependencies: []string{"mcma_service"},
+		F:            sweepJobProfiles,
+	})
+	resource.AddTestSweepers("mcma_resource", &resource.Sweeper{
+		Name: "mcma_resource",
+		F:    sweepMcmaResources,
+	})
+}
+
+// getSweeperProviderConfigs returns the raw provider configurations of the registries used by the acceptance tests.
+func getSweeperProviderConfigs() []map[string]interface{} {
+	var configs []map[string]interface{}
+	if serviceRegistryUrl := os.Getenv("MCMA_AWS_SERVICE_REGISTRY_URL"); serviceRegistryUrl != "" {
+		configs = append(configs, map[string]interface{}{
+			"service_registry_url": serviceRegistryUrl,
+			"aws4_auth": []interface{}{
+				map[string]interface{}{
+					"region":  os.Getenv("MCMA_AWS_REGION"),
+					"profile": os.Getenv("MCMA_AWS_PROFILE"),
+				},
+			},
+		})
+	}
+	if serviceRegistryUrl := os.Getenv("MCMA_API_KEY_SERVICE_REGISTRY_URL"); serviceRegistryUrl != "" {
+		configs = append(configs, map[string]interface{}{
+			"service_registry_url": serviceRegistryUrl,
+			"mcma_api_key_auth": []interface{}{
+				map[string]interface{}{
+					"api_key": os.Getenv("MCMA_API_KEY"),
+				},
+			},
+		})
+	}
+	return configs
+}
+
+// sweepRegistries calls sweep with a resource manager for each registry used by the acceptance tests, and returns
+// the errors of all of them.
+func sweepRegistries(sweep func(resourceManager *mcmaclient.ResourceManager) []error) error {
+	configs := getSweeperProviderConfigs()
+	if len(configs) == 0 {
+		return fmt.Errorf("MCMA_AWS_SERVICE_REGISTRY_URL or MCMA_API_KEY_SERVICE_REGISTRY_URL must be set to run sweepers")
+	}
+
+	var errs []string
+	for _, config := range configs {
+		provider := Provider()
+		if diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(config)); diags.HasError() {
+			for _, d := range diags {
+				errs = append(errs, fmt.Sprintf("error configuring provider for %s: %s", config["service_registry_url"], d.Summary))
+			}
+			continue
+		}
+		for _, err := range sweep(provider.Meta().(*providerMeta).resourceManager) {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func isSweepable(name string) bool {
+	return strings.HasPrefix(name, testAccNamePrefix)
+}
+
+func sweepServices(_ string) error {
+	return sweepRegistries(func(resourceManager *mcmaclient.ResourceManager) []error {
+		results, err := resourceManager.Query(context.Background(), reflect.TypeOf(mcmamodel.Service{}), map[string]string{})
+		if err != nil {
+			return []error{fmt.Errorf("error querying services: %s", err)}
+		}
+		var errs []error
+		for _, result := range results {
+			service := result.(mcmamodel.Service)
+			if !isSweepable(service.Name) {
+				continue
+			}
+			if err := resourceManager.Delete(context.Background(), reflect.TypeOf(mcmamodel.Service{}), service.Id); err != nil {
+				errs = append(errs, fmt.Errorf("error deleting service %s: %s", service.Id, err))
+			}
+		}
+		return errs
+	})
+}
+
+func sweepJobProfiles(_ string) error {
+	return sweepRegistries(func(resourceManager *mcmaclient.ResourceManager) []error {
+		results, err := resourceManager.Query(context.Background(), reflect.TypeOf(mcmamodel.JobProfile{}), map[string]string{})
+		if err != nil {
+			return []error{fmt.Errorf("error querying job profiles: %s", err)}
+		}
+		var errs []error
+		for _, result := range results {
+			jobProfile := result.(mcmamodel.JobProfile)
+			if !isSweepable(jobProfile.Name) {
+				continue
+			}
+			if err := resourceManager.Delete(context.Background(), reflect.TypeOf(mcmamodel.JobProfile{}), jobProfile.Id); err != nil {
+				errs = append(errs, fmt.Errorf("error deleting job profile %s: %s", jobProfile.Id, err))
+			}
+		}
+		return errs
+	})
+}
+
+// getSweeperResourceTypes returns the types of the MCMA resources to sweep.
+func getSweeperResourceTypes() []string {
+	resourceTypes := os.Getenv("MCMA_SWEEP_RESOURCE_TYPES")
+	if resourceTypes == "" {
+		return []string{"BMContent"}
+	}
+	return strings.Split(resourceTypes, ",")
+}
+
+// getMcmaResourceName returns the name of an MCMA resource, which is either a top-level property or, like for
+// BMContent, a property of its metadata.
+func getMcmaResourceName(mcmaResource map[string]interface{}) string {
+	if name, ok := mcmaResource["name"].(string); ok {
+		return name
+	}
+	if metadata, ok := mcmaResource["metadata"].(map[string]interface{}); ok {
+		name, _ := metadata["name"].(string)
+		return name
+	}
+	return ""
+}
+
+func sweepMcmaResources(_ string) error {
+	return sweepRegistries(func(resourceManager *mcmaclient.ResourceManager) []error {
+		var errs []error
+		for _, resourceType := range getSweeperResourceTypes() {
+			resourceType = strings.TrimSpace(resourceType)
+			results, err := resourceManager.QueryResources(context.Background(), resourceType, map[string]string{})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error querying resources of type %s: %s", resourceType, err))
+				continue
+			}
+			for _, result := range results {
+				resourceId, _ := result["id"].(string)
+				if resourceId == "" || !isSweepable(getMcmaResourceName(result)) {
+					continue
+				}
+				if err := resourceManager.DeleteResource(context.Background(), resourceType, resourceId); err != nil {
+					errs = append(errs, fmt.Errorf("error deleting resource %s: %s", resourceId, err))
+				}
+			}
+		}
+		return errs
+	})
+}
+
+// TestAccSweepers runs the sweepers against a fake registry holding test objects and objects that must be kept.
+func TestAccSweepers(t *testing.T) {
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		t.Skipf("acceptance tests skipped unless env '%s' set", resource.EnvTfAcc)
+	}
+
+	registry := newDefaultFakeRegistry(mcmatest.AuthTypeMcmaApiKey)
+	defer registry.Close()
+	t.Setenv("MCMA_AWS_SERVICE_REGISTRY_URL", "")
+	t.Setenv("MCMA_API_KEY_SERVICE_REGISTRY_URL", registry.URL())
+	t.Setenv("MCMA_API_KEY", mcmatest.McmaApiKey)
+	t.Setenv("MCMA_SWEEP_RESOURCE_TYPES", "")
+
+	testName := acctest.RandomWithPrefix(testAccNamePrefix)
+	_, err := registry.SeedJSON([]byte(`[
+		{"@type": "JobProfile", "id": "${registry_url}/api/job-profiles/test", "name": "` + testName + `"},
+		{"@type": "Service", "name": "` + testName + `", "jobProfileIds": ["${registry_url}/api/job-profiles/test"]},
+		{"@type": "BMContent", "metadata": {"name": "` + testName + `"}},
+		{"@type": "JobProfile", "name": "Transcode"},
+		{"@type": "Service", "name": "Transcode Service"},
+		{"@type": "BMContent", "metadata": {"name": "Asset"}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sweep := range []func(string) error{sweepServices, sweepJobProfiles, sweepMcmaResources} {
+		if err := sweep(""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry.AssertResourceExists(t, "Service", map[string]string{"name": "Transcode Service"})
+	registry.AssertResourceExists(t, "JobProfile", map[string]string{"name": "Transcode"})
+	for _, resourceType := range []string{"Service", "JobProfile"} {
+		for _, r := range registry.Resources(resourceType) {
+			if isSweepable(r["name"].(string)) {
+				t.Errorf("expected %s %s to be swept", resourceType, r["id"])
+			}
+		}
+	}
+	bmContents := registry.Resources("BMContent")
+	if len(bmContents) != 1 || getMcmaResourceName(bmContents[0]) != "Asset" {
+		t.Errorf("expected only the BMContent that is not a test object to be kept, got %v", bmContents)
+	}
+}
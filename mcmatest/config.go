@@ -0,0 +1,82 @@
+package mcmatest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AuthBlock is an auth block of the provider configuration.
+type AuthBlock interface {
+	GetText() string
+}
+
+// AWS4Auth is an aws4_auth block of the provider configuration. Empty attributes are left out.
+type AWS4Auth struct {
+	AuthType  string
+	Region    string
+	Profile   string
+	AccessKey string
+	SecretKey string
+}
+
+func (authBlock AWS4Auth) GetText() string {
+	return getAuthBlockText("aws4_auth", [][2]string{
+		{"auth_type", authBlock.AuthType},
+		{"region", authBlock.Region},
+		{"profile", authBlock.Profile},
+		{"access_key", authBlock.AccessKey},
+		{"secret_key", authBlock.SecretKey},
+	})
+}
+
+// McmaApiKeyAuth is an mcma_api_key_auth block of the provider configuration. Empty attributes are left out.
+type McmaApiKeyAuth struct {
+	AuthType string
+	ApiKey   string
+}
+
+func (authBlock McmaApiKeyAuth) GetText() string {
+	return getAuthBlockText("mcma_api_key_auth", [][2]string{
+		{"auth_type", authBlock.AuthType},
+		{"api_key", authBlock.ApiKey},
+	})
+}
+
+func getAuthBlockText(blockType string, attributes [][2]string) string {
+	var b strings.Builder
+	b.WriteString("  " + blockType + " {\n")
+	for _, attribute := range attributes {
+		if attribute[1] != "" {
+			b.WriteString(fmt.Sprintf("    %s = %q\n", attribute[0], attribute[1]))
+		}
+	}
+	b.WriteString("  }\n")
+	return b.String()
+}
+
+// ProviderConfig returns the HCL of an mcma provider block for the given service registry. The service registry auth
+// type is left out if it is empty.
+func ProviderConfig(serviceRegistryUrl string, serviceRegistryAuthType string, authBlocks ...AuthBlock) string {
+	providerConfig := "provider \"mcma\" {\n"
+	providerConfig += fmt.Sprintf("  service_registry_url = %q\n", serviceRegistryUrl)
+	if serviceRegistryAuthType != "" {
+		providerConfig += fmt.Sprintf("  service_registry_auth_type = %q\n", serviceRegistryAuthType)
+	}
+	for _, authBlock := range authBlocks {
+		providerConfig += authBlock.GetText()
+	}
+	providerConfig += "}\n"
+	return providerConfig
+}
+
+// ProviderConfig returns the HCL of an mcma provider block for the registry, with the credentials it accepts.
+func (r *Registry) ProviderConfig() string {
+	switch r.authType {
+	case AuthTypeAWS4:
+		return ProviderConfig(r.URL(), "", AWS4Auth{Region: AwsRegion, AccessKey: AwsAccessKey, SecretKey: AwsSecretKey})
+	case AuthTypeMcmaApiKey:
+		return ProviderConfig(r.URL(), "", McmaApiKeyAuth{ApiKey: McmaApiKey})
+	default:
+		return ProviderConfig(r.URL(), "")
+	}
+}
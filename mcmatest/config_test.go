@@ -0,0 +1,38 @@
+package mcmatest
+
+import (
+	"testing"
+)
+
+func TestProviderConfig(t *testing.T) {
+	providerConfig := ProviderConfig("https://registry.example.com/api/", "AWS4",
+		AWS4Auth{Region: "eu-west-1", Profile: "default"},
+		McmaApiKeyAuth{AuthType: "McmaApiKey", ApiKey: "key"})
+
+	expected := `provider "mcma" {
+  service_registry_url = "https://registry.example.com/api/"
+  service_registry_auth_type = "AWS4"
+  aws4_auth {
+    region = "eu-west-1"
+    profile = "default"
+  }
+  mcma_api_key_auth {
+    auth_type = "McmaApiKey"
+    api_key = "key"
+  }
+}
+`
+	if providerConfig != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, providerConfig)
+	}
+}
+
+func TestRegistry_ProviderConfig(t *testing.T) {
+	r := NewRegistry(AuthTypeMcmaApiKey)
+	defer r.Close()
+
+	expected := ProviderConfig(r.URL(), "", McmaApiKeyAuth{ApiKey: McmaApiKey})
+	if providerConfig := r.ProviderConfig(); providerConfig != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, providerConfig)
+	}
+}
@@ -0,0 +1,28 @@
+// Package mcmatest provides a fake MCMA service registry and helpers to test Terraform modules and Go tooling built on
+// the MCMA provider without access to a real registry.
+//
+// A Registry is an in-memory service registry served over HTTP. It serves services and job profiles, any resource
+// type added with AddResourceEndpoint, and runs the jobs of the types added with AddJobEndpoint, either straight away
+// or for the duration set with SetJobDuration. It can be seeded from
+// JSON fixtures, records the requests it receives, checks AWS4 signatures or MCMA API keys, and can inject latency,
+// server errors and eventual consistency delays.
+//
+// With the acceptance test framework of the Terraform plugin SDK, a test can use the configuration returned by
+// ProviderConfig to run the provider against a registry:
+//
+//	registry := mcmatest.NewRegistry(mcmatest.AuthTypeMcmaApiKey)
+//	defer registry.Close()
+//
+//	resource.Test(t, resource.TestCase{
+//		ProviderFactories: providerFactories,
+//		Steps: []resource.TestStep{
+//			{
+//				Config: registry.ProviderConfig() + moduleConfig,
+//				Check: func(*terraform.State) error {
+//					registry.AssertResourceCount(t, "Service", 2)
+//					return nil
+//				},
+//			},
+//		},
+//	})
+package mcmatest
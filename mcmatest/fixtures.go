@@ -0,0 +1,55 @@
+package mcmatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RegistryUrlPlaceholder is replaced with the url of the registry server in fixtures, so that they can set the IDs of
+// resources and reference them, e.g. "${registry_url}/api/job-profiles/transcode".
+const RegistryUrlPlaceholder = "${registry_url}"
+
+// SeedJSON seeds the registry with the resources in a JSON fixture, which is either a single resource or an array of
+// resources. Every resource must have an @type for which the registry has a resource endpoint. Resources without an id
+// get a generated one.
+func (r *Registry) SeedJSON(fixture []byte) ([]map[string]interface{}, error) {
+	fixture = []byte(strings.ReplaceAll(string(fixture), RegistryUrlPlaceholder, r.server.URL))
+
+	var resources []map[string]interface{}
+	if strings.HasPrefix(strings.TrimSpace(string(fixture)), "[") {
+		if err := json.Unmarshal(fixture, &resources); err != nil {
+			return nil, fmt.Errorf("error parsing fixture: %s", err)
+		}
+	} else {
+		var resource map[string]interface{}
+		if err := json.Unmarshal(fixture, &resource); err != nil {
+			return nil, fmt.Errorf("error parsing fixture: %s", err)
+		}
+		resources = append(resources, resource)
+	}
+
+	var seeded []map[string]interface{}
+	for i, resource := range resources {
+		resourceType, _ := resource["@type"].(string)
+		if resourceType == "" {
+			return seeded, fmt.Errorf("resource %d of fixture has no @type", i)
+		}
+		stored, err := r.Seed(resourceType, resource)
+		if err != nil {
+			return seeded, err
+		}
+		seeded = append(seeded, stored)
+	}
+	return seeded, nil
+}
+
+// SeedFile seeds the registry with the resources in a JSON fixture file, as described for SeedJSON.
+func (r *Registry) SeedFile(fileName string) ([]map[string]interface{}, error) {
+	fixture, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return r.SeedJSON(fixture)
+}
@@ -0,0 +1,117 @@
+package mcmatest
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"testing"
+)
+
+// Request is a request received by a Registry.
+type Request struct {
+	Method string
+	// Path is the cleaned path of the request, e.g. /api/services/1
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// StatusCode is the status code of the response
+	StatusCode int
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (w *statusRecorder) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *Registry) record(req *http.Request, body []byte, statusCode int) {
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, Request{
+		Method:     req.Method,
+		Path:       path.Clean(req.URL.Path),
+		Query:      req.URL.Query(),
+		Header:     req.Header.Clone(),
+		Body:       body,
+		StatusCode: statusCode,
+	})
+}
+
+// Requests returns the requests received by the registry, in the order they were received.
+func (r *Registry) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Request(nil), r.requests...)
+}
+
+// ResetRequests forgets the requests received so far.
+func (r *Registry) ResetRequests() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+// CountRequests returns the number of requests received with the given method and path. An empty method matches any
+// method.
+func (r *Registry) CountRequests(method string, urlPath string) int {
+	count := 0
+	urlPath = path.Clean(urlPath)
+	for _, request := range r.Requests() {
+		if (method == "" || request.Method == method) && request.Path == urlPath {
+			count++
+		}
+	}
+	return count
+}
+
+// AssertRequested fails the test if the registry has not received a request with the given method and path, e.g.
+// POST /api/services.
+func (r *Registry) AssertRequested(t testing.TB, method string, urlPath string) {
+	t.Helper()
+	if r.CountRequests(method, urlPath) == 0 {
+		t.Errorf("expected a %s request to %s", method, urlPath)
+	}
+}
+
+// AssertNotRequested fails the test if the registry has received a request with the given method and path.
+func (r *Registry) AssertNotRequested(t testing.TB, method string, urlPath string) {
+	t.Helper()
+	if count := r.CountRequests(method, urlPath); count != 0 {
+		t.Errorf("expected no %s request to %s, got %d", method, urlPath, count)
+	}
+}
+
+// AssertResourceCount fails the test if the registry does not hold the given number of resources of the given type.
+// The service registry itself and the service of the endpoints added with AddResourceEndpoint count as services.
+func (r *Registry) AssertResourceCount(t testing.TB, resourceType string, expected int) {
+	t.Helper()
+	if count := len(r.Resources(resourceType)); count != expected {
+		t.Errorf("expected %d resources of type %s, got %d", expected, resourceType, count)
+	}
+}
+
+// AssertResourceExists fails the test if the registry holds no resource of the given type whose top-level properties
+// have the given values, and returns the first one that does.
+func (r *Registry) AssertResourceExists(t testing.TB, resourceType string, properties map[string]string) map[string]interface{} {
+	t.Helper()
+	filter := make(map[string][]string, len(properties))
+	for k, v := range properties {
+		filter[k] = []string{v}
+	}
+	for _, resource := range r.Resources(resourceType) {
+		if matches(resource, filter) {
+			return resource
+		}
+	}
+	t.Errorf("expected a resource of type %s with properties %v", resourceType, properties)
+	return nil
+}
@@ -0,0 +1,515 @@
+package mcmatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Auth types that a Registry can require requests to be authenticated with.
+const (
+	AuthTypeNone       = ""
+	AuthTypeAWS4       = "AWS4"
+	AuthTypeMcmaApiKey = "McmaApiKey"
+)
+
+// Job statuses set by a Registry.
+const (
+	JobStatusRunning   = "Running"
+	JobStatusCompleted = "Completed"
+	JobStatusFailed    = "Failed"
+	JobStatusCanceled  = "Canceled"
+)
+
+// JobRunner runs a job submitted to a Registry, returning its output or the reason it failed. A nil runner leaves jobs
+// running until they are canceled.
+type JobRunner func(jobInput map[string]interface{}) (map[string]interface{}, error)
+
+// Registry is an in-memory MCMA service registry served over HTTP. It is safe for concurrent use.
+type Registry struct {
+	server   *httptest.Server
+	authType string
+
+	mu               sync.Mutex
+	collections      map[string]string
+	jobRunners       map[string]JobRunner
+	resources        map[string][]resourceVersion
+	ids              []string
+	nextId           int
+	lastModified     time.Time
+	latency          time.Duration
+	consistencyDelay time.Duration
+	jobDuration      time.Duration
+	failures         []int
+	requests         []Request
+}
+
+// resourceVersion is a version of a resource, which becomes visible to reads at visibleAt. A nil resource marks a
+// deletion. jobResult marks the version holding the result of a job that runs for the job duration.
+type resourceVersion struct {
+	resource  map[string]interface{}
+	visibleAt time.Time
+	jobResult bool
+}
+
+const dateFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// NewRegistry starts a registry that requires requests to be authenticated with the given auth type, i.e. one of
+// AuthTypeNone, AuthTypeAWS4 or AuthTypeMcmaApiKey. It serves the services and job profiles of the registry at
+// URL() + "services" and URL() + "job-profiles". Close must be called to stop it.
+func NewRegistry(authType string) *Registry {
+	r := &Registry{
+		authType:    authType,
+		collections: make(map[string]string),
+		jobRunners:  make(map[string]JobRunner),
+		resources:   make(map[string][]resourceVersion),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+
+	r.collections["/api/services"] = "Service"
+	r.collections["/api/job-profiles"] = "JobProfile"
+	r.store(r.server.URL+"/api/services/registry", map[string]interface{}{
+		"@type":    "Service",
+		"name":     "Service Registry",
+		"authType": authType,
+		"resources": []interface{}{
+			r.resourceEndpoint("Service", "/api/services"),
+			r.resourceEndpoint("JobProfile", "/api/job-profiles"),
+		},
+	}, false)
+
+	return r
+}
+
+// Close stops the registry.
+func (r *Registry) Close() {
+	r.server.Close()
+}
+
+// URL returns the url of the service registry, as configured for the provider.
+func (r *Registry) URL() string {
+	return r.server.URL + "/api/"
+}
+
+func (r *Registry) resourceEndpoint(resourceType string, collectionPath string) map[string]interface{} {
+	return map[string]interface{}{
+		"@type":        "ResourceEndpoint",
+		"resourceType": resourceType,
+		"httpEndpoint": r.server.URL + collectionPath,
+	}
+}
+
+// AddResourceEndpoint serves resources of the given type at the given path, e.g. /api/bm-contents, and registers the
+// endpoint in a service of the registry so that the provider can find it.
+func (r *Registry) AddResourceEndpoint(resourceType string, collectionPath string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collectionPath = path.Clean("/" + collectionPath)
+	r.collections[collectionPath] = resourceType
+
+	serviceId := r.server.URL + "/api/services/resources"
+	service := r.latest(serviceId)
+	if service == nil {
+		service = map[string]interface{}{
+			"@type":     "Service",
+			"name":      "Resource Service",
+			"authType":  r.authType,
+			"resources": []interface{}{},
+		}
+	}
+	service["resources"] = append(service["resources"].([]interface{}), r.resourceEndpoint(resourceType, collectionPath))
+	r.store(serviceId, service, false)
+}
+
+// AddJobEndpoint serves jobs of the given type at the given path. Jobs are run with run when they are submitted, and
+// can be canceled with a POST to their ID followed by /cancel.
+func (r *Registry) AddJobEndpoint(jobType string, collectionPath string, run JobRunner) {
+	r.AddResourceEndpoint(jobType, collectionPath)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobRunners[jobType] = run
+}
+
+// Seed stores a resource of the given type, which is visible straight away, and returns it with its ID and dates. The
+// ID of the resource is kept if it is set, otherwise one is generated.
+func (r *Registry) Seed(resourceType string, resource map[string]interface{}) (map[string]interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for collectionPath, t := range r.collections {
+		if t != resourceType {
+			continue
+		}
+		resource = copyResource(resource)
+		resource["@type"] = resourceType
+		id, _ := resource["id"].(string)
+		if id == "" {
+			id = r.newId(collectionPath)
+		} else if !strings.HasPrefix(id, r.server.URL+collectionPath+"/") {
+			return nil, fmt.Errorf("id %s of resource of type %s is not in %s%s", id, resourceType, r.server.URL, collectionPath)
+		}
+		return r.store(id, resource, false), nil
+	}
+	return nil, fmt.Errorf("no resource endpoint for type %s", resourceType)
+}
+
+// SetLatency delays every response by the given duration.
+func (r *Registry) SetLatency(latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latency = latency
+}
+
+// SetConsistencyDelay delays the visibility of writes to reads by the given duration, like an eventually consistent
+// store would.
+func (r *Registry) SetConsistencyDelay(delay time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.consistencyDelay = delay
+}
+
+// SetJobDuration makes the jobs submitted from now on run for the given duration: they are returned as Running, and
+// the result of their runner only becomes visible to reads once the duration has elapsed, unless they are canceled
+// before. Jobs whose runner is nil keep running until they are canceled whatever the duration.
+func (r *Registry) SetJobDuration(duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobDuration = duration
+}
+
+// FailNext makes the next requests fail with the given status codes, e.g. 503, one per request.
+func (r *Registry) FailNext(statusCodes ...int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, statusCodes...)
+}
+
+// Resource returns the latest version of the resource with the given ID, whether it is visible to reads yet or not,
+// or nil if it does not exist.
+func (r *Registry) Resource(id string) map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.latest(id)
+}
+
+// Resources returns the latest versions of the resources of the given type, in the order they were created.
+func (r *Registry) Resources(resourceType string) []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var resources []map[string]interface{}
+	for _, id := range r.ids {
+		if resource := r.latest(id); resource != nil && resource["@type"] == resourceType {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+func (r *Registry) newId(collectionPath string) string {
+	r.nextId++
+	return fmt.Sprintf("%s%s/%d", r.server.URL, collectionPath, r.nextId)
+}
+
+// latest returns a copy of the latest version of a resource, whether it is visible or not.
+func (r *Registry) latest(id string) map[string]interface{} {
+	versions := r.resources[id]
+	if len(versions) == 0 {
+		return nil
+	}
+	return copyResource(versions[len(versions)-1].resource)
+}
+
+// visible returns a copy of the latest version of a resource that is visible to reads.
+func (r *Registry) visible(id string) map[string]interface{} {
+	now := time.Now()
+	versions := r.resources[id]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].visibleAt.After(now) {
+			return copyResource(versions[i].resource)
+		}
+	}
+	return nil
+}
+
+// store writes a new version of a resource, setting its ID and dates. If delayed, it only becomes visible to reads
+// after the consistency delay.
+func (r *Registry) store(id string, resource map[string]interface{}, delayed bool) map[string]interface{} {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	if !now.After(r.lastModified) {
+		now = r.lastModified.Add(time.Millisecond)
+	}
+	r.lastModified = now
+
+	previous := r.latest(id)
+	if previous == nil {
+		if len(r.resources[id]) == 0 {
+			r.ids = append(r.ids, id)
+		}
+		resource["dateCreated"] = now.Format(dateFormat)
+	} else {
+		resource["dateCreated"] = previous["dateCreated"]
+	}
+	resource["id"] = id
+	resource["dateModified"] = now.Format(dateFormat)
+
+	r.storeVersion(id, resource, delayed)
+	return copyResource(resource)
+}
+
+func (r *Registry) storeVersion(id string, resource map[string]interface{}, delayed bool) {
+	visibleAt := time.Now()
+	if delayed {
+		visibleAt = visibleAt.Add(r.consistencyDelay)
+	}
+	r.resources[id] = append(r.resources[id], resourceVersion{resource: copyResource(resource), visibleAt: visibleAt})
+}
+
+func copyResource(resource map[string]interface{}) map[string]interface{} {
+	if resource == nil {
+		return nil
+	}
+	jsonBytes, _ := json.Marshal(resource)
+	var copied map[string]interface{}
+	_ = json.Unmarshal(jsonBytes, &copied)
+	return copied
+}
+
+func (r *Registry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	recorder := &statusRecorder{ResponseWriter: w}
+	defer func() {
+		r.record(req, body, recorder.statusCode)
+	}()
+	if err != nil {
+		writeProblem(recorder, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	r.mu.Lock()
+	latency := r.latency
+	failure := 0
+	if len(r.failures) > 0 {
+		failure = r.failures[0]
+		r.failures = r.failures[1:]
+	}
+	r.mu.Unlock()
+
+	time.Sleep(latency)
+	if failure != 0 {
+		writeProblem(recorder, failure, "injected failure")
+		return
+	}
+	if status, message := r.authenticate(req, body); status != 0 {
+		writeProblem(recorder, status, message)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handle(recorder, req, body)
+}
+
+func (r *Registry) handle(w http.ResponseWriter, req *http.Request, body []byte) {
+	urlPath := path.Clean(req.URL.Path)
+	if resourceType, found := r.collections[urlPath]; found {
+		switch req.Method {
+		case http.MethodGet:
+			r.query(w, urlPath, req.URL.Query())
+		case http.MethodPost:
+			r.create(w, urlPath, resourceType, body)
+		default:
+			writeProblem(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	id := r.server.URL + urlPath
+	if strings.HasSuffix(id, "/cancel") && req.Method == http.MethodPost {
+		r.cancel(w, strings.TrimSuffix(id, "/cancel"))
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		resource := r.visible(id)
+		if resource == nil {
+			writeProblem(w, http.StatusNotFound, "resource not found")
+			return
+		}
+		writeJson(w, http.StatusOK, resource)
+	case http.MethodPut:
+		if r.latest(id) == nil {
+			writeProblem(w, http.StatusNotFound, "resource not found")
+			return
+		}
+		resource, ok := decodeResource(w, body)
+		if !ok {
+			return
+		}
+		writeJson(w, http.StatusOK, r.store(id, resource, true))
+	case http.MethodDelete:
+		if r.latest(id) == nil {
+			writeProblem(w, http.StatusNotFound, "resource not found")
+			return
+		}
+		r.storeVersion(id, nil, true)
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeProblem(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// query returns the visible resources of a collection whose properties have the values in the query string.
+func (r *Registry) query(w http.ResponseWriter, collectionPath string, filter map[string][]string) {
+	prefix := r.server.URL + collectionPath + "/"
+	results := make([]interface{}, 0)
+	for _, id := range r.ids {
+		if !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		resource := r.visible(id)
+		if resource != nil && matches(resource, filter) {
+			results = append(results, resource)
+		}
+	}
+	writeJson(w, http.StatusOK, results)
+}
+
+func matches(resource map[string]interface{}, filter map[string][]string) bool {
+	for key, values := range filter {
+		var value string
+		switch v := resource[key].(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			value = strconv.FormatBool(v)
+		default:
+			return false
+		}
+		if value != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Registry) create(w http.ResponseWriter, collectionPath string, resourceType string, body []byte) {
+	resource, ok := decodeResource(w, body)
+	if !ok {
+		return
+	}
+	if resource["@type"] == nil {
+		resource["@type"] = resourceType
+	}
+
+	run, isJob := r.jobRunners[resourceType]
+	if isJob && run != nil && r.jobDuration > 0 {
+		running := copyResource(resource)
+		runJob(running, nil)
+		created := r.store(r.newId(collectionPath), running, true)
+		runJob(resource, run)
+		r.storeJobResult(created, resource)
+		writeJson(w, http.StatusCreated, created)
+		return
+	}
+	if isJob {
+		runJob(resource, run)
+	}
+
+	writeJson(w, http.StatusCreated, r.store(r.newId(collectionPath), resource, true))
+}
+
+// storeJobResult stores a job that finished running as a version of the created job that becomes visible to reads
+// once the job duration has elapsed.
+func (r *Registry) storeJobResult(created map[string]interface{}, job map[string]interface{}) {
+	finishedAt := time.Now().Add(r.jobDuration)
+	id := created["id"].(string)
+	job["id"] = id
+	job["dateCreated"] = created["dateCreated"]
+	job["dateModified"] = finishedAt.UTC().Truncate(time.Millisecond).Format(dateFormat)
+	r.resources[id] = append(r.resources[id], resourceVersion{resource: copyResource(job), visibleAt: finishedAt, jobResult: true})
+}
+
+func runJob(job map[string]interface{}, run JobRunner) {
+	job["status"] = JobStatusRunning
+	job["progress"] = 0
+	if run == nil {
+		return
+	}
+
+	jobInput, _ := job["jobInput"].(map[string]interface{})
+	jobOutput, err := run(jobInput)
+	if err != nil {
+		job["status"] = JobStatusFailed
+		job["error"] = map[string]interface{}{
+			"@type": "ProblemDetail",
+			"type":  "uri://mcma.ebu.ch/rfc7807/mcmatest/job-failed",
+			"title": err.Error(),
+		}
+		return
+	}
+
+	if jobOutput == nil {
+		jobOutput = make(map[string]interface{})
+	}
+	jobOutput["@type"] = "JobParameterBag"
+	job["status"] = JobStatusCompleted
+	job["progress"] = 100
+	job["jobOutput"] = jobOutput
+}
+
+func (r *Registry) cancel(w http.ResponseWriter, jobId string) {
+	// a job that is still running for the job duration is canceled before its result becomes visible
+	now := time.Now()
+	versions := r.resources[jobId]
+	if n := len(versions); n > 0 && versions[n-1].jobResult && versions[n-1].visibleAt.After(now) {
+		r.resources[jobId] = versions[:n-1]
+	}
+
+	job := r.latest(jobId)
+	if job == nil {
+		writeProblem(w, http.StatusNotFound, "job not found")
+		return
+	}
+	if status, _ := job["status"].(string); status == JobStatusRunning {
+		job["status"] = JobStatusCanceled
+		r.store(jobId, job, true)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func decodeResource(w http.ResponseWriter, body []byte) (map[string]interface{}, bool) {
+	var resource map[string]interface{}
+	if err := json.Unmarshal(body, &resource); err != nil || resource == nil {
+		writeProblem(w, http.StatusBadRequest, "request body must be a JSON object")
+		return nil, false
+	}
+	return resource, true
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeProblem(w http.ResponseWriter, statusCode int, detail string) {
+	writeJson(w, statusCode, map[string]interface{}{
+		"type":   "about:blank",
+		"title":  http.StatusText(statusCode),
+		"status": statusCode,
+		"detail": detail,
+	})
+}
@@ -0,0 +1,345 @@
+package mcmatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const testJobType = "TestJob"
+
+func newTestRegistry(authType string) *Registry {
+	r := NewRegistry(authType)
+	r.AddResourceEndpoint("BMContent", "/api/bm-contents")
+	r.AddJobEndpoint(testJobType, "/api/jobs", func(jobInput map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"echo": jobInput}, nil
+	})
+	return r
+}
+
+func sendRequest(t *testing.T, method string, url string, body interface{}, authenticate func(*http.Request, []byte)) (int, interface{}) {
+	t.Helper()
+
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		if bodyBytes, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authenticate != nil {
+		authenticate(req, bodyBytes)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var result interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return resp.StatusCode, result
+}
+
+func TestRegistry_resources(t *testing.T) {
+	r := newTestRegistry(AuthTypeNone)
+	defer r.Close()
+
+	status, result := sendRequest(t, http.MethodGet, r.URL()+"services", nil, nil)
+	if status != http.StatusOK || len(result.([]interface{})) != 2 {
+		t.Fatalf("expected the registry and resource services, got %d %v", status, result)
+	}
+
+	status, result = sendRequest(t, http.MethodPost, r.URL()+"bm-contents", map[string]interface{}{"name": "asset"}, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("expected resource to be created, got %d %v", status, result)
+	}
+	created := result.(map[string]interface{})
+	id := created["id"].(string)
+	if created["@type"] != "BMContent" || created["dateCreated"] == nil || created["dateModified"] == nil {
+		t.Errorf("expected type and dates to be set, got %v", created)
+	}
+
+	created["name"] = "renamed"
+	status, result = sendRequest(t, http.MethodPut, id, created, nil)
+	if status != http.StatusOK || result.(map[string]interface{})["dateModified"] == created["dateModified"] {
+		t.Errorf("expected resource to be updated with a new dateModified, got %d %v", status, result)
+	}
+
+	status, result = sendRequest(t, http.MethodGet, r.URL()+"bm-contents?name=renamed", nil, nil)
+	if status != http.StatusOK || len(result.([]interface{})) != 1 {
+		t.Errorf("expected query to find the resource, got %d %v", status, result)
+	}
+	status, result = sendRequest(t, http.MethodGet, r.URL()+"bm-contents?name=asset", nil, nil)
+	if status != http.StatusOK || len(result.([]interface{})) != 0 {
+		t.Errorf("expected query not to find the resource, got %d %v", status, result)
+	}
+
+	if status, _ = sendRequest(t, http.MethodDelete, id, nil, nil); status != http.StatusOK {
+		t.Errorf("expected resource to be deleted, got %d", status)
+	}
+	if status, _ = sendRequest(t, http.MethodGet, id, nil, nil); status != http.StatusNotFound {
+		t.Errorf("expected resource not to be found after deletion, got %d", status)
+	}
+}
+
+func TestRegistry_jobs(t *testing.T) {
+	r := newTestRegistry(AuthTypeNone)
+	defer r.Close()
+	r.AddJobEndpoint("FailingJob", "/api/failing-jobs", func(map[string]interface{}) (map[string]interface{}, error) {
+		return nil, fmt.Errorf("no GPU available")
+	})
+	r.AddJobEndpoint("LongRunningJob", "/api/long-running-jobs", nil)
+
+	_, result := sendRequest(t, http.MethodPost, r.URL()+"jobs", map[string]interface{}{
+		"@type":    testJobType,
+		"jobInput": map[string]interface{}{"inputFile": "s3://bucket/file.mp4"},
+	}, nil)
+	job := result.(map[string]interface{})
+	if job["status"] != JobStatusCompleted || job["jobOutput"].(map[string]interface{})["echo"] == nil {
+		t.Errorf("expected completed job with output, got %v", job)
+	}
+
+	_, result = sendRequest(t, http.MethodPost, r.URL()+"failing-jobs", map[string]interface{}{"@type": "FailingJob"}, nil)
+	job = result.(map[string]interface{})
+	if job["status"] != JobStatusFailed || job["error"].(map[string]interface{})["title"] != "no GPU available" {
+		t.Errorf("expected failed job with error, got %v", job)
+	}
+
+	_, result = sendRequest(t, http.MethodPost, r.URL()+"long-running-jobs", map[string]interface{}{"@type": "LongRunningJob"}, nil)
+	jobId := result.(map[string]interface{})["id"].(string)
+	if status, _ := sendRequest(t, http.MethodPost, jobId+"/cancel", nil, nil); status != http.StatusOK {
+		t.Errorf("expected job to be canceled, got %d", status)
+	}
+	if _, result = sendRequest(t, http.MethodGet, jobId, nil, nil); result.(map[string]interface{})["status"] != JobStatusCanceled {
+		t.Errorf("expected canceled job, got %v", result)
+	}
+}
+
+func TestRegistry_jobDuration(t *testing.T) {
+	r := newTestRegistry(AuthTypeNone)
+	defer r.Close()
+	r.SetJobDuration(100 * time.Millisecond)
+
+	_, result := sendRequest(t, http.MethodPost, r.URL()+"jobs", map[string]interface{}{"@type": testJobType}, nil)
+	jobId := result.(map[string]interface{})["id"].(string)
+	if status := result.(map[string]interface{})["status"]; status != JobStatusRunning {
+		t.Errorf("expected job to be running when submitted, got %v", status)
+	}
+	if _, result = sendRequest(t, http.MethodGet, jobId, nil, nil); result.(map[string]interface{})["status"] != JobStatusRunning {
+		t.Errorf("expected job to be running before the job duration, got %v", result)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, result = sendRequest(t, http.MethodGet, jobId, nil, nil); result.(map[string]interface{})["status"] != JobStatusCompleted {
+		t.Errorf("expected job to be completed after the job duration, got %v", result)
+	}
+
+	_, result = sendRequest(t, http.MethodPost, r.URL()+"jobs", map[string]interface{}{"@type": testJobType}, nil)
+	jobId = result.(map[string]interface{})["id"].(string)
+	if status, _ := sendRequest(t, http.MethodPost, jobId+"/cancel", nil, nil); status != http.StatusOK {
+		t.Errorf("expected job to be canceled, got %d", status)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, result = sendRequest(t, http.MethodGet, jobId, nil, nil); result.(map[string]interface{})["status"] != JobStatusCanceled {
+		t.Errorf("expected job canceled before the job duration to stay canceled, got %v", result)
+	}
+}
+
+func TestRegistry_faults(t *testing.T) {
+	r := newTestRegistry(AuthTypeNone)
+	defer r.Close()
+
+	r.FailNext(http.StatusServiceUnavailable, http.StatusInternalServerError)
+	for _, expected := range []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK} {
+		if status, _ := sendRequest(t, http.MethodGet, r.URL()+"services", nil, nil); status != expected {
+			t.Errorf("expected status %d, got %d", expected, status)
+		}
+	}
+
+	r.SetLatency(50 * time.Millisecond)
+	start := time.Now()
+	sendRequest(t, http.MethodGet, r.URL()+"services", nil, nil)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected response to be delayed, got it after %s", elapsed)
+	}
+	r.SetLatency(0)
+
+	r.SetConsistencyDelay(100 * time.Millisecond)
+	_, result := sendRequest(t, http.MethodPost, r.URL()+"bm-contents", map[string]interface{}{}, nil)
+	id := result.(map[string]interface{})["id"].(string)
+	if status, _ := sendRequest(t, http.MethodGet, id, nil, nil); status != http.StatusNotFound {
+		t.Errorf("expected resource not to be visible straight away, got %d", status)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if status, _ := sendRequest(t, http.MethodGet, id, nil, nil); status != http.StatusOK {
+		t.Errorf("expected resource to be visible after the consistency delay, got %d", status)
+	}
+}
+
+func TestRegistry_auth(t *testing.T) {
+	signWith := func(accessKey string, secretKey string) func(*http.Request, []byte) {
+		return func(req *http.Request, body []byte) {
+			signer := v4.NewSigner(credentials.NewStaticCredentials(accessKey, secretKey, ""))
+			if _, err := signer.Sign(req, bytes.NewReader(body), "execute-api", AwsRegion, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	withApiKey := func(apiKey string) func(*http.Request, []byte) {
+		return func(req *http.Request, _ []byte) {
+			req.Header.Set("x-mcma-api-key", apiKey)
+		}
+	}
+
+	cases := []struct {
+		authType     string
+		authenticate func(*http.Request, []byte)
+		expected     int
+	}{
+		{AuthTypeAWS4, signWith(AwsAccessKey, AwsSecretKey), http.StatusCreated},
+		{AuthTypeAWS4, signWith(AwsAccessKey, "wrong-secret-key"), http.StatusForbidden},
+		{AuthTypeAWS4, signWith("AKIAOTHER", AwsSecretKey), http.StatusForbidden},
+		{AuthTypeAWS4, nil, http.StatusForbidden},
+		{AuthTypeMcmaApiKey, withApiKey(McmaApiKey), http.StatusCreated},
+		{AuthTypeMcmaApiKey, withApiKey("wrong-api-key"), http.StatusUnauthorized},
+		{AuthTypeMcmaApiKey, nil, http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		r := newTestRegistry(c.authType)
+		status, result := sendRequest(t, http.MethodPost, r.URL()+"bm-contents", map[string]interface{}{"name": "asset"}, c.authenticate)
+		if status != c.expected {
+			t.Errorf("%s: expected status %d, got %d %v", c.authType, c.expected, status, result)
+		}
+		r.Close()
+	}
+}
+
+func TestRegistry_seedJSON(t *testing.T) {
+	r := newTestRegistry(AuthTypeNone)
+	defer r.Close()
+
+	seeded, err := r.SeedJSON([]byte(`[
+		{"@type": "JobProfile", "id": "${registry_url}/api/job-profiles/transcode", "name": "Transcode"},
+		{"@type": "BMContent", "name": "asset", "profile": "${registry_url}/api/job-profiles/transcode"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seeded) != 2 || seeded[0]["id"] != r.URL()+"job-profiles/transcode" {
+		t.Fatalf("expected fixture to be seeded with its IDs, got %v", seeded)
+	}
+
+	_, result := sendRequest(t, http.MethodGet, r.URL()+"job-profiles?name=Transcode", nil, nil)
+	if len(result.([]interface{})) != 1 {
+		t.Errorf("expected seeded job profile to be visible, got %v", result)
+	}
+	r.AssertResourceExists(t, "BMContent", map[string]string{"name": "asset", "profile": r.URL() + "job-profiles/transcode"})
+
+	if _, err = r.SeedJSON([]byte(`{"name": "untyped"}`)); err == nil {
+		t.Error("expected error for resource without @type")
+	}
+	if _, err = r.SeedJSON([]byte(`{"@type": "Unknown"}`)); err == nil {
+		t.Error("expected error for resource type without endpoint")
+	}
+	if _, err = r.SeedJSON([]byte(`{"@type": "BMContent", "id": "${registry_url}/api/job-profiles/1"}`)); err == nil {
+		t.Error("expected error for id outside of the resource endpoint")
+	}
+}
+
+func TestRegistry_seedFile(t *testing.T) {
+	r := newTestRegistry(AuthTypeNone)
+	defer r.Close()
+
+	fileName := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(fileName, []byte(`{"@type": "BMContent", "name": "asset"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.SeedFile(fileName); err != nil {
+		t.Fatal(err)
+	}
+	r.AssertResourceCount(t, "BMContent", 1)
+
+	if _, err := r.SeedFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing fixture file")
+	}
+}
+
+func TestRegistry_requests(t *testing.T) {
+	r := newTestRegistry(AuthTypeMcmaApiKey)
+	defer r.Close()
+
+	withApiKey := func(req *http.Request, _ []byte) {
+		req.Header.Set("x-mcma-api-key", McmaApiKey)
+	}
+	sendRequest(t, http.MethodPost, r.URL()+"bm-contents", map[string]interface{}{"name": "asset"}, withApiKey)
+	sendRequest(t, http.MethodGet, r.URL()+"services?name=Resource%20Service", nil, nil)
+
+	requests := r.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(requests[0].Body, &body); err != nil || body["name"] != "asset" {
+		t.Errorf("expected request body to be recorded, got %s", requests[0].Body)
+	}
+	if requests[0].StatusCode != http.StatusCreated || requests[0].Header.Get("x-mcma-api-key") != McmaApiKey {
+		t.Errorf("expected status and headers to be recorded, got %v", requests[0])
+	}
+	if requests[1].StatusCode != http.StatusUnauthorized || requests[1].Query.Get("name") != "Resource Service" {
+		t.Errorf("expected status and query to be recorded, got %v", requests[1])
+	}
+
+	r.AssertRequested(t, http.MethodPost, "/api/bm-contents")
+	r.AssertNotRequested(t, http.MethodDelete, "/api/bm-contents")
+	if count := r.CountRequests("", "/api/services"); count != 1 {
+		t.Errorf("expected 1 request to services, got %d", count)
+	}
+
+	r.ResetRequests()
+	if requests = r.Requests(); len(requests) != 0 {
+		t.Errorf("expected requests to be reset, got %v", requests)
+	}
+}
+
+func TestRegistry_assertions(t *testing.T) {
+	r := newTestRegistry(AuthTypeNone)
+	defer r.Close()
+
+	if _, err := r.Seed("BMContent", map[string]interface{}{"name": "asset"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &testing.T{}
+	r.AssertResourceCount(mock, "Service", 2)
+	r.AssertResourceCount(mock, "BMContent", 1)
+	r.AssertResourceExists(mock, "BMContent", map[string]string{"name": "asset"})
+	if mock.Failed() {
+		t.Error("expected assertions to pass")
+	}
+
+	for name, assert := range map[string]func(testing.TB){
+		"count":     func(tb testing.TB) { r.AssertResourceCount(tb, "BMContent", 2) },
+		"exists":    func(tb testing.TB) { r.AssertResourceExists(tb, "BMContent", map[string]string{"name": "other"}) },
+		"requested": func(tb testing.TB) { r.AssertRequested(tb, http.MethodGet, "/api/services") },
+	} {
+		mock = &testing.T{}
+		assert(mock)
+		if !mock.Failed() {
+			t.Errorf("%s: expected assertion to fail", name)
+		}
+	}
+}